@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// List of rules reported by accessibility audit.
+const (
+	A11yButtonEmpty = `a11y/button-empty`
+	A11yHeadingSkip = `a11y/heading-skip`
+	A11yHtmlLang    = `a11y/html-lang`
+	A11yIdDuplicate = `a11y/id-duplicate`
+	A11yImgAlt      = `a11y/img-alt`
+	A11yInputLabel  = `a11y/input-label`
+	A11yLinkEmpty   = `a11y/link-empty`
+	A11yLinkText    = `a11y/link-text`
+)
+
+// listVagueLinkText contains the text of link that does not describe its
+// target, in lower case.
+var listVagueLinkText = []string{
+	`click`,
+	`click here`,
+	`here`,
+	`learn more`,
+	`link`,
+	`more`,
+	`read more`,
+	`this`,
+	`this link`,
+}
+
+// a11yName collect the accessible name of the opened anchor or button.
+type a11yName struct {
+	// link the href of anchor.
+	link string
+
+	text strings.Builder
+
+	line   int
+	column int
+
+	isOpen bool
+
+	// hasLabel true if the element has attribute "aria-label",
+	// "aria-labelledby", or "title".
+	hasLabel bool
+}
+
+// a11yInput the form input that does not have label, yet.
+type a11yInput struct {
+	id     string
+	name   string
+	line   int
+	column int
+}
+
+// auditA11y parse the HTML content and return the common static
+// accessibility issues.
+func auditA11y(content []byte) (listIssue []Issue) {
+	var (
+		tokenizer = html.NewTokenizer(bytes.NewReader(content))
+
+		anchor a11yName
+		button a11yName
+
+		// listInput contains the inputs that is not inside element
+		// "label" and does not have attribute for label, to be
+		// checked against the "for" attribute of labels at the end.
+		listInput []a11yInput
+
+		// labelFor contains the value of attribute "for" in all
+		// labels.
+		labelFor = map[string]bool{}
+
+		// listId contains the id of elements that has been found.
+		listId = map[string]bool{}
+
+		line   = 1
+		column = 1
+
+		// nLabel the number of opened element "label".
+		nLabel int
+
+		// prevLevel the level of previous heading.
+		prevLevel int
+
+		hasHtml bool
+	)
+
+	var add = func(rule, msg, link string, line, column int) {
+		listIssue = append(listIssue, Issue{
+			Rule:    rule,
+			Message: msg,
+			Link:    link,
+			Line:    line,
+			Column:  column,
+		})
+	}
+
+	var closeName = func(name *a11yName, isAnchor bool) {
+		if !name.isOpen {
+			return
+		}
+		name.isOpen = false
+		var text = strings.Join(strings.Fields(name.text.String()), ` `)
+		name.text.Reset()
+		if name.hasLabel {
+			return
+		}
+		switch {
+		case text == `` && isAnchor:
+			add(A11yLinkEmpty, `link does not have text`, name.link,
+				name.line, name.column)
+		case text == ``:
+			add(A11yButtonEmpty, `button does not have text`, ``,
+				name.line, name.column)
+		case isAnchor && slices.Contains(listVagueLinkText,
+			strings.ToLower(strings.Trim(text, `.!>»→ `))):
+			add(A11yLinkText, fmt.Sprintf(`link text %q does not`+
+				` describe its target`, text), name.link,
+				name.line, name.column)
+		}
+	}
+
+	var openName = func(name *a11yName, token html.Token, line, column int) {
+		name.isOpen = true
+		name.line = line
+		name.column = column
+		name.hasLabel = hasAriaLabel(token)
+	}
+
+	var writeName = func(text string) {
+		if anchor.isOpen {
+			anchor.text.WriteString(text)
+			anchor.text.WriteByte(' ')
+		}
+		if button.isOpen {
+			button.text.WriteString(text)
+			button.text.WriteByte(' ')
+		}
+	}
+
+	for {
+		var (
+			tokenType = tokenizer.Next()
+			startLine = line
+			startCol  = column
+		)
+		line, column = advancePosition(tokenizer.Raw(), line, column)
+
+		switch tokenType {
+		case html.ErrorToken:
+			closeName(&anchor, true)
+			closeName(&button, false)
+			for _, input := range listInput {
+				if input.id != `` && labelFor[input.id] {
+					continue
+				}
+				add(A11yInputLabel, fmt.Sprintf(`%s does not have`+
+					` label`, input.name), ``, input.line,
+					input.column)
+			}
+			if !hasHtml {
+				add(A11yHtmlLang, `page does not have element html`+
+					` with attribute lang`, ``, 0, 0)
+			}
+			return listIssue
+
+		case html.TextToken:
+			writeName(string(tokenizer.Text()))
+
+		case html.EndTagToken:
+			var name, _ = tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.A:
+				closeName(&anchor, true)
+			case atom.Button:
+				closeName(&button, false)
+			case atom.Label:
+				if nLabel > 0 {
+					nLabel--
+				}
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			var (
+				token   = tokenizer.Token()
+				isStart = tokenType == html.StartTagToken
+			)
+
+			var id, _ = attrValue(token.Attr, `id`)
+			if id != `` {
+				if listId[id] {
+					add(A11yIdDuplicate, fmt.Sprintf(`duplicate id %q`,
+						id), ``, startLine, startCol)
+				}
+				listId[id] = true
+			}
+
+			switch token.DataAtom {
+			case atom.Html:
+				hasHtml = true
+				var lang, _ = attrValue(token.Attr, `lang`)
+				if strings.TrimSpace(lang) == `` {
+					add(A11yHtmlLang, `element html does not have`+
+						` attribute lang`, ``, startLine, startCol)
+				}
+
+			case atom.A:
+				closeName(&anchor, true)
+				var href, ok = attrValue(token.Attr, `href`)
+				if !ok {
+					continue
+				}
+				openName(&anchor, token, startLine, startCol)
+				anchor.link = href
+				if !isStart {
+					closeName(&anchor, true)
+				}
+
+			case atom.Button:
+				closeName(&button, false)
+				openName(&button, token, startLine, startCol)
+				if !isStart {
+					closeName(&button, false)
+				}
+
+			case atom.Img:
+				var alt, ok = attrValue(token.Attr, `alt`)
+				if !ok {
+					var src, _ = attrValue(token.Attr, `src`)
+					add(A11yImgAlt, `image does not have attribute`+
+						` alt`, src, startLine, startCol)
+				}
+				writeName(alt)
+
+			case atom.Label:
+				var val, _ = attrValue(token.Attr, `for`)
+				if val != `` {
+					labelFor[val] = true
+				}
+				if isStart {
+					nLabel++
+				}
+
+			case atom.Input, atom.Select, atom.Textarea:
+				if token.DataAtom == atom.Input {
+					var inputType, _ = attrValue(token.Attr, `type`)
+					switch strings.ToLower(inputType) {
+					case `hidden`, `submit`, `reset`, `button`,
+						`image`:
+						continue
+					}
+				}
+				if nLabel > 0 {
+					continue
+				}
+				if hasAriaLabel(token) {
+					continue
+				}
+				listInput = append(listInput, a11yInput{
+					id:     id,
+					name:   token.Data,
+					line:   startLine,
+					column: startCol,
+				})
+
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				var level = int(token.Data[1] - '0')
+				if prevLevel > 0 && level > prevLevel+1 {
+					add(A11yHeadingSkip, fmt.Sprintf(`heading %s`+
+						` skip level after h%d`, token.Data,
+						prevLevel), ``, startLine, startCol)
+				}
+				prevLevel = level
+			}
+		}
+	}
+}
+
+// hasAriaLabel return true if the element has non-empty attribute
+// "aria-label", "aria-labelledby", or "title".
+func hasAriaLabel(token html.Token) bool {
+	for _, key := range []string{`aria-label`, `aria-labelledby`, `title`} {
+		var val, _ = attrValue(token.Attr, key)
+		if strings.TrimSpace(val) != `` {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_a11y(t *testing.T) {
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     filepath.Join(`testdata`, `a11y`),
+		NoCache: true,
+		A11y:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Issue{
+		`testdata/a11y/index.html`: {{
+			Rule:    brokenlinks.A11yHtmlLang,
+			Message: `element html does not have attribute lang`,
+			Line:    5,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yHeadingSkip,
+			Message: `heading h3 skip level after h1`,
+			Line:    11,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yImgAlt,
+			Message: `image does not have attribute alt`,
+			Link:    `/logo.png`,
+			Line:    12,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yLinkText,
+			Message: `link text "Click here" does not describe its target`,
+			Link:    `/about.html`,
+			Line:    15,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yLinkEmpty,
+			Message: `link does not have text`,
+			Link:    `/about.html`,
+			Line:    17,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yButtonEmpty,
+			Message: `button does not have text`,
+			Line:    19,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yInputLabel,
+			Message: `input does not have label`,
+			Line:    22,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yInputLabel,
+			Message: `textarea does not have label`,
+			Line:    27,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.A11yIdDuplicate,
+			Message: `duplicate id "top"`,
+			Line:    30,
+			Column:  1,
+		}},
+	}
+	test.Assert(t, `Issues`, exp, got.Issues)
+}
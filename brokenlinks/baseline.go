@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// Baseline contains list of broken links that are accepted, loaded from
+// file [Options.BaselineFile].
+//
+// The baseline file is a JSON file with the following format,
+//
+//	{
+//		"entries": [{
+//			"page": <string>,
+//			"link": <string>,
+//			"expires": <string>,
+//			"reason": <string>
+//		}, ...]
+//	}
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry define the broken link in the page that is accepted.
+// The Page and Link may contains wildcard "*" that match any characters,
+// including "/".
+type BaselineEntry struct {
+	// expiresAt the time after the Expires date.
+	expiresAt time.Time
+
+	// Page the URL of page where the broken link found.
+	// Empty Page match any page.
+	Page string `json:"page,omitempty"`
+
+	// Link the URL of the broken link.
+	Link string `json:"link"`
+
+	// Expires the last date, in format "YYYY-MM-DD", where the entry is
+	// accepted.
+	// Empty Expires means the entry never expired.
+	Expires string `json:"expires,omitempty"`
+
+	// Reason why the broken link is accepted.
+	Reason string `json:"reason,omitempty"`
+}
+
+// loadBaseline load and validate the baseline from JSON file.
+func loadBaseline(file string) (baseline *Baseline, err error) {
+	var content []byte
+
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline = &Baseline{}
+	err = json.Unmarshal(content, baseline)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, file, err)
+	}
+
+	for x, entry := range baseline.Entries {
+		if entry.Link == `` {
+			return nil, fmt.Errorf(`%s: entry #%d: empty link`,
+				file, x)
+		}
+		if entry.Expires == `` {
+			continue
+		}
+		var expires time.Time
+		expires, err = time.Parse(time.DateOnly, entry.Expires)
+		if err != nil {
+			return nil, fmt.Errorf(`%s: entry #%d: invalid expires %q`,
+				file, x, entry.Expires)
+		}
+		baseline.Entries[x].expiresAt = expires.AddDate(0, 0, 1)
+	}
+	return baseline, nil
+}
+
+// isExpired return true if the entry has been expired at time now.
+func (entry *BaselineEntry) isExpired(now time.Time) bool {
+	if entry.expiresAt.IsZero() {
+		return false
+	}
+	return !now.Before(entry.expiresAt)
+}
+
+// match return true if the entry match with the broken link in the page.
+func (entry *BaselineEntry) match(page, link string) bool {
+	if entry.Page != `` && !internal.MatchWildcard(entry.Page, page) {
+		return false
+	}
+	return internal.MatchWildcard(entry.Link, link)
+}
+
+// apply move the broken links in the result that match with one of the
+// baseline entries into [Result.Suppressed].
+// If withStale is true, the entries that does not match with any broken
+// links or has been expired are stored in [Result.StaleBaseline].
+func (baseline *Baseline) apply(result *Result, withStale bool) {
+	var (
+		now        = internal.TimeNow()
+		brokenLink = map[string][]Broken{}
+	)
+	for page, listBroken := range result.BrokenLinks {
+		for _, broken := range listBroken {
+			if baseline.suppress(result, now, page, broken) {
+				continue
+			}
+			brokenLink[page] = append(brokenLink[page], broken)
+		}
+	}
+	result.BrokenLinks = brokenLink
+
+	if !withStale {
+		return
+	}
+	result.StaleBaseline = baseline.stale(result, now)
+}
+
+// suppress add the broken link in the page into [Result.Suppressed] and
+// return true if its match with one of the entries.
+func (baseline *Baseline) suppress(
+	result *Result, now time.Time, page string, broken Broken,
+) bool {
+	if baseline.matchEntry(now, page, broken.Link) < 0 {
+		return false
+	}
+	if result.Suppressed == nil {
+		result.Suppressed = map[string][]Broken{}
+	}
+	result.Suppressed[page] = append(result.Suppressed[page], broken)
+	return true
+}
+
+// matchEntry return the index of the first entry that is not expired and
+// match with the page and link, or -1 if none of them match.
+func (baseline *Baseline) matchEntry(now time.Time, page, link string) int {
+	for x, entry := range baseline.Entries {
+		if entry.isExpired(now) {
+			continue
+		}
+		if entry.match(page, link) {
+			return x
+		}
+	}
+	return -1
+}
+
+// stale return the entries that does not match with any of the broken
+// links in [Result.Suppressed] or has been expired.
+func (baseline *Baseline) stale(result *Result, now time.Time) (
+	listStale []BaselineEntry,
+) {
+	var listUsed = make([]bool, len(baseline.Entries))
+	for page, listBroken := range result.Suppressed {
+		for _, broken := range listBroken {
+			var x = baseline.matchEntry(now, page, broken.Link)
+			if x >= 0 {
+				listUsed[x] = true
+			}
+		}
+	}
+	for x, entry := range baseline.Entries {
+		if !listUsed[x] {
+			listStale = append(listStale, entry)
+		}
+	}
+	return listStale
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+func TestScan_baseline(t *testing.T) {
+	const testUrl = `http://` + testAddress
+
+	var orgTimeNow = internal.TimeNow
+	t.Cleanup(func() {
+		internal.TimeNow = orgTimeNow
+	})
+	internal.TimeNow = func() time.Time {
+		return time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var opts = brokenlinks.Options{
+		Url:          testUrl + `/page2`,
+		BaselineFile: `testdata/baseline.json`,
+	}
+
+	var got *brokenlinks.Result
+	var err error
+	got, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = &brokenlinks.Result{
+		BrokenLinks: map[string][]brokenlinks.Broken{
+			testUrl + `/page2`: {{
+				Link:    testUrl + `/broken.png`,
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    7,
+				Column:  5,
+				Count:   1,
+			}, {
+				Link:    testUrl + `/page2/broken/relative`,
+				Text:    `broken relative link`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    10,
+				Column:  5,
+				Count:   2,
+			}},
+		},
+		Suppressed: map[string][]brokenlinks.Broken{
+			testUrl + `/page2`: {{
+				Link:    testUrl + `/page2/broken2.png`,
+				Text:    `Broken image 2`,
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    8,
+				Column:  5,
+				Count:   1,
+			}},
+		},
+		StaleBaseline: []brokenlinks.BaselineEntry{{
+			Link:    testUrl + `/broken.png`,
+			Expires: `2026-01-31`,
+			Reason:  `The image will be restored in January.`,
+		}, {
+			Page:   `*/page3`,
+			Link:   `*`,
+			Reason: `The page has been removed.`,
+		}},
+	}
+
+	// Compare the JSON to skip the unexported fields.
+	expJson, _ := json.MarshalIndent(exp, ``, `  `)
+	gotJson, _ := json.MarshalIndent(got, ``, `  `)
+	test.Assert(t, `baseline`, string(expJson), string(gotJson))
+}
+
+func TestScan_baselineInvalid(t *testing.T) {
+	var baselineFile = filepath.Join(t.TempDir(), `baseline.json`)
+	var content = []byte(`{"entries":[{"page":"/page"}]}`)
+	var err = os.WriteFile(baselineFile, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var opts = brokenlinks.Options{
+		Url:          `http://` + testAddress,
+		BaselineFile: baselineFile,
+	}
+	_, err = brokenlinks.Scan(opts)
+	var expError = `Scan: Options: ` + baselineFile + `: entry #0: empty link`
+	if err == nil {
+		t.Fatalf(`expecting error %q, got nil`, expError)
+	}
+	test.Assert(t, `error`, expError, err.Error())
+}
@@ -4,8 +4,9 @@
 package brokenlinks
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 )
 
 const Version = `0.1.0`
@@ -14,8 +15,21 @@ const Version = `0.1.0`
 // reachable during GET or HEAD, either timeout or IP or domain not exist.
 const StatusBadLink = 700
 
+// ErrInterrupted returned by [ScanContext] when the context is cancelled
+// before the scan completed.
+var ErrInterrupted = errors.New(`scan interrupted`)
+
 // Scan the URL for broken links.
 func Scan(opts Options) (result *Result, err error) {
+	return ScanContext(context.Background(), opts)
+}
+
+// ScanContext scan the URL for broken links until completed or until the
+// ctx is cancelled.
+// If the ctx is cancelled, it return the partial result with error
+// [ErrInterrupted], and the state is saved into [Options.StateDir] if
+// its set.
+func ScanContext(ctx context.Context, opts Options) (result *Result, err error) {
 	var logp = `Scan`
 
 	err = opts.init()
@@ -25,20 +39,37 @@ func Scan(opts Options) (result *Result, err error) {
 
 	var wrk *worker
 
-	wrk, err = newWorker(opts)
+	wrk, err = newWorker(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf(`%s: %w`, logp, err)
 	}
 
 	result, err = wrk.run()
-	if err != nil {
+	var isInterrupted = errors.Is(err, ErrInterrupted)
+	if err != nil && !isInterrupted {
+		var errClose = wrk.close()
+		if errClose != nil {
+			opts.Logger.Error(logp, `error`, errClose)
+		}
 		return nil, fmt.Errorf(`%s: %w`, logp, err)
 	}
 
-	err = wrk.cache.Save()
+	if opts.baseline != nil {
+		// Scanning only the pages from past result, or interrupted
+		// scan, does not visit all links, so the stale entries is
+		// unknown.
+		var withStale = wrk.pastResult == nil && !isInterrupted
+		opts.baseline.apply(result, withStale)
+		result.sort()
+	}
+
+	err = wrk.close()
 	if err != nil {
-		log.Printf(`%s: %s`, logp, err)
+		opts.Logger.Error(logp, `error`, err)
 	}
 
+	if isInterrupted {
+		return result, fmt.Errorf(`%s: %w`, logp, ErrInterrupted)
+	}
 	return result, nil
 }
@@ -4,17 +4,25 @@
 package brokenlinks_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	libnet "git.sr.ht/~shulhan/pakakeh.go/lib/net"
 	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
 
+	"git.sr.ht/~shulhan/jarink"
 	"git.sr.ht/~shulhan/jarink/brokenlinks"
 	"git.sr.ht/~shulhan/jarink/internal"
 )
@@ -44,14 +52,16 @@ const testAddressSlow = `127.0.0.1:11839`
 func TestMain(m *testing.M) {
 	log.SetFlags(0)
 
-	var orgCacheFile = internal.CacheFile
-	var tmpCacheFile = filepath.Join(os.TempDir(), `cache.json`)
+	// Use new cache file on each run, so the pages cached from the
+	// previous run does not affect the result.
+	var tmpDir, err = os.MkdirTemp(``, `jarink-brokenlinks-`)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var tmpCacheFile = filepath.Join(tmpDir, `cache.json`)
 	internal.CacheFile = func() (string, error) {
 		return tmpCacheFile, nil
 	}
-	defer func() {
-		internal.CacheFile = orgCacheFile
-	}()
 
 	var httpDirWeb = http.Dir(`testdata/web`)
 	var fshandle = http.FileServer(httpDirWeb)
@@ -61,7 +71,7 @@ func TestMain(m *testing.M) {
 	go testInsecureServer(fshandle)
 	go runServerSlow(testAddressSlow)
 
-	var err = libnet.WaitAlive(`tcp`, testAddress, 5*time.Second)
+	err = libnet.WaitAlive(`tcp`, testAddress, 5*time.Second)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -78,7 +88,9 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
-	os.Exit(m.Run())
+	var code = m.Run()
+	_ = os.RemoveAll(tmpDir)
+	os.Exit(code)
 }
 
 func testServer(fshandle http.Handler) {
@@ -251,41 +263,83 @@ func TestScan(t *testing.T) {
 		exp: map[string][]brokenlinks.Broken{
 			testUrl: []brokenlinks.Broken{
 				{
-					Link: testUrl + `/broken.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/broken.png`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    7,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link: testUrl + `/brokenPage`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/brokenPage`,
+					Text:    `Broken page`,
+					Element: `a@href`,
+					Code:    http.StatusNotFound,
+					Line:    8,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link:  `http://127.0.0.1:abc`,
-					Error: `parse "http://127.0.0.1:abc": invalid port ":abc" after host`,
-					Code:  brokenlinks.StatusBadLink,
+					Link:    `http://127.0.0.1:abc`,
+					Error:   `parse "http://127.0.0.1:abc": invalid port ":abc" after host`,
+					Text:    `Invalid URL port`,
+					Element: `a@href`,
+					Code:    brokenlinks.StatusBadLink,
+					Line:    21,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link:  `http:/127.0.0.1:11836`,
-					Error: `Get "http:/127.0.0.1:11836": http: no Host in request URL`,
-					Code:  brokenlinks.StatusBadLink,
+					Link:    `http:/127.0.0.1:11836`,
+					Error:   `Get "http:/127.0.0.1:11836": http: no Host in request URL`,
+					Text:    `Invalid external URL`,
+					Element: `a@href`,
+					Code:    brokenlinks.StatusBadLink,
+					Line:    18,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link:  `https://domain`,
-					Error: `Get "https://domain": dial tcp: lookup domain: no such host`,
-					Code:  700,
+					Link:    `https://domain`,
+					Error:   `Get "https://domain": dial tcp: lookup domain: no such host`,
+					Text:    `Invalid domain`,
+					Element: `a@href`,
+					Code:    700,
+					Line:    34,
+					Column:  5,
+					Count:   1,
 				},
 			},
 			testUrl + `/broken.html`: []brokenlinks.Broken{
 				{
-					Link: testUrl + `/brokenPage`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/brokenPage`,
+					Element: `a@href`,
+					Code:    http.StatusNotFound,
+					Line:    8,
+					Column:  5,
+					Count:   1,
 				},
 			},
 			testUrl + `/page2`: []brokenlinks.Broken{
 				{
-					Link: testUrl + `/broken.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/broken.png`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    7,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link: testUrl + `/page2/broken/relative`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken/relative`,
+					Text:    `broken relative link`,
+					Element: `a@href`,
+					Code:    http.StatusNotFound,
+					Line:    10,
+					Column:  5,
+					Count:   2,
 				}, {
-					Link: testUrl + `/page2/broken2.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken2.png`,
+					Text:    `Broken image 2`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    8,
+					Column:  5,
+					Count:   1,
 				},
 			},
 		},
@@ -299,14 +353,28 @@ func TestScan(t *testing.T) {
 		exp: map[string][]brokenlinks.Broken{
 			testUrl + `/page2`: []brokenlinks.Broken{
 				{
-					Link: testUrl + `/broken.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/broken.png`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    7,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link: testUrl + `/page2/broken/relative`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken/relative`,
+					Text:    `broken relative link`,
+					Element: `a@href`,
+					Code:    http.StatusNotFound,
+					Line:    10,
+					Column:  5,
+					Count:   2,
 				}, {
-					Link: testUrl + `/page2/broken2.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken2.png`,
+					Text:    `Broken image 2`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    8,
+					Column:  5,
+					Count:   1,
 				},
 			},
 		},
@@ -358,14 +426,28 @@ func TestScan_pastResult(t *testing.T) {
 		exp: map[string][]brokenlinks.Broken{
 			testUrl + `/page2`: []brokenlinks.Broken{
 				{
-					Link: testUrl + `/broken.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/broken.png`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    7,
+					Column:  5,
+					Count:   1,
 				}, {
-					Link: testUrl + `/page2/broken/relative`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken/relative`,
+					Text:    `broken relative link`,
+					Element: `a@href`,
+					Code:    http.StatusNotFound,
+					Line:    10,
+					Column:  5,
+					Count:   2,
 				}, {
-					Link: testUrl + `/page2/broken2.png`,
-					Code: http.StatusNotFound,
+					Link:    testUrl + `/page2/broken2.png`,
+					Text:    `Broken image 2`,
+					Element: `img@src`,
+					Code:    http.StatusNotFound,
+					Line:    8,
+					Column:  5,
+					Count:   1,
 				},
 			},
 		},
@@ -409,16 +491,31 @@ func TestScan_slow(t *testing.T) {
 	var expResult = &brokenlinks.Result{
 		BrokenLinks: map[string][]brokenlinks.Broken{
 			testUrl + `/slow1`: []brokenlinks.Broken{{
-				Link: testUrl + `/slow3/sub`,
-				Code: http.StatusForbidden,
+				Link:    testUrl + `/slow3/sub`,
+				Text:    `Slow 3, sub`,
+				Element: `a@href`,
+				Code:    http.StatusForbidden,
+				Line:    4,
+				Column:  5,
+				Count:   1,
 			}},
 			testUrl + `/slow2`: []brokenlinks.Broken{{
-				Link: testUrl + `/slow3/sub`,
-				Code: http.StatusForbidden,
+				Link:    testUrl + `/slow3/sub`,
+				Text:    `Slow 3, sub`,
+				Element: `a@href`,
+				Code:    http.StatusForbidden,
+				Line:    4,
+				Column:  5,
+				Count:   1,
 			}},
 			testUrl + `/slow3`: []brokenlinks.Broken{{
-				Link: testUrl + `/slow3/sub`,
-				Code: http.StatusForbidden,
+				Link:    testUrl + `/slow3/sub`,
+				Text:    `Slow 3, sub`,
+				Element: `a@href`,
+				Code:    http.StatusForbidden,
+				Line:    4,
+				Column:  5,
+				Count:   1,
 			}},
 		},
 	}
@@ -436,6 +533,14 @@ func TestBrokenlinks_cache(t *testing.T) {
 		return gotCacheFile, nil
 	}
 
+	var orgTimeNow = internal.TimeNow
+	t.Cleanup(func() {
+		internal.TimeNow = orgTimeNow
+	})
+	internal.TimeNow = func() time.Time {
+		return time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	}
+
 	var testUrl = `http://` + testAddress
 	var opts = brokenlinks.Options{
 		Url:          testUrl,
@@ -445,13 +550,564 @@ func TestBrokenlinks_cache(t *testing.T) {
 
 	var err error
 	_, err = brokenlinks.Scan(opts)
-	gotCache, err := os.ReadFile(gotCacheFile)
+	rawCache, err := os.ReadFile(gotCacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Last-Modified of internal pages depends on the modification
+	// time of files in testdata, so clear it before comparing.
+	// The error on unknown host depends on the resolver, for example
+	// "lookup domain on 127.0.0.53:53: no such host", so normalise it
+	// into "lookup domain: no such host".
+	var cache jarink.Cache
+	err = json.Unmarshal(rawCache, &cache)
 	if err != nil {
 		t.Fatal(err)
 	}
+	for _, scannedLink := range cache.ScannedLinks {
+		scannedLink.LastModified = ``
+		scannedLink.Error = normaliseLookupError(scannedLink.Error)
+	}
+	gotCache, err := json.MarshalIndent(&cache, ``, `  `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotCache = append(gotCache, '\n')
+
 	expCache, err := os.ReadFile(expCacheFile)
 	if err != nil {
 		t.Fatal(err)
 	}
-	test.Assert(t, `cache`, string(gotCache), string(expCache))
+	test.Assert(t, `cache`, string(expCache), string(gotCache))
+}
+
+// normaliseLookupError remove the address of resolver from the error
+// of DNS lookup, for example from
+// "lookup domain on 127.0.0.53:53: no such host" into
+// "lookup domain: no such host".
+func normaliseLookupError(errMsg string) string {
+	var before, after, ok = strings.Cut(errMsg, `lookup `)
+	if !ok {
+		return errMsg
+	}
+	var host, rest, found = strings.Cut(after, ` on `)
+	if !found || strings.Contains(host, `:`) {
+		return errMsg
+	}
+	var _, tail, hasTail = strings.Cut(rest, `: `)
+	if !hasTail {
+		return errMsg
+	}
+	return before + `lookup ` + host + `: ` + tail
+}
+
+func TestScan_logger(t *testing.T) {
+	const testUrl = `http://` + testAddress + `/page2`
+
+	var logbuf bytes.Buffer
+	var opts = brokenlinks.Options{
+		Url:   testUrl,
+		Cache: jarink.NewMemoryCache(),
+		Logger: slog.New(slog.NewJSONHandler(&logbuf,
+			&slog.HandlerOptions{Level: slog.LevelInfo})),
+	}
+
+	var err error
+	_, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type logFetch struct {
+		Msg    string `json:"msg"`
+		Method string `json:"method"`
+		Url    string `json:"url"`
+		Parent string `json:"parent"`
+		Status int    `json:"status"`
+	}
+	var got []logFetch
+	var dec = json.NewDecoder(&logbuf)
+	for dec.More() {
+		var entry logFetch
+		err = dec.Decode(&entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Url == testUrl || entry.Parent == testUrl {
+			got = append(got, entry)
+		}
+	}
+	var exp = logFetch{
+		Msg:    `fetch`,
+		Method: `GET`,
+		Url:    testUrl,
+		Status: http.StatusOK,
+	}
+	if len(got) == 0 {
+		t.Fatal(`expecting log entry for fetch, got none`)
+	}
+	test.Assert(t, `first fetch`, exp, got[0])
+}
+
+func TestScan_conditionalRequest(t *testing.T) {
+	const testUrl = `http://` + testAddress + `/page2`
+
+	var cache = jarink.NewMemoryCache()
+
+	var scan = func() (result *brokenlinks.Result, status int) {
+		var logbuf bytes.Buffer
+		var opts = brokenlinks.Options{
+			Url:   testUrl,
+			Cache: cache,
+			Logger: slog.New(slog.NewJSONHandler(&logbuf,
+				&slog.HandlerOptions{Level: slog.LevelInfo})),
+		}
+		var err error
+		result, err = brokenlinks.Scan(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		type logFetch struct {
+			Url    string `json:"url"`
+			Status int    `json:"status"`
+		}
+		var dec = json.NewDecoder(&logbuf)
+		for dec.More() {
+			var entry logFetch
+			err = dec.Decode(&entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry.Url == testUrl {
+				status = entry.Status
+			}
+		}
+		return result, status
+	}
+
+	var expResult, gotStatus = scan()
+	test.Assert(t, `first scan status`, http.StatusOK, gotStatus)
+
+	var scannedLink, err = cache.Get(testUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scannedLink == nil || scannedLink.LastModified == `` {
+		t.Fatalf(`expecting page %s stored with Last-Modified`, testUrl)
+	}
+
+	var gotResult *brokenlinks.Result
+	gotResult, gotStatus = scan()
+	test.Assert(t, `second scan status`, http.StatusNotModified, gotStatus)
+	test.Assert(t, `second scan result`, expResult, gotResult)
+}
+
+// TestScan_pageWithoutValidator test that the internal page without
+// header "ETag" and "Last-Modified" is not stored in the cache.
+func TestScan_pageWithoutValidator(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(
+		func(resp http.ResponseWriter, _ *http.Request) {
+			resp.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+			_, _ = resp.Write([]byte(`<html><body>Page</body></html>`))
+		}))
+	t.Cleanup(srv.Close)
+
+	var cache = jarink.NewMemoryCache()
+	var _, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:   srv.URL,
+		Cache: cache,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scannedLink *jarink.ScannedLink
+	scannedLink, err = cache.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `cached page`, (*jarink.ScannedLink)(nil), scannedLink)
+}
+
+func TestScan_ignoreMarker(t *testing.T) {
+	const testUrl = `http://` + testAddress + `/ignore`
+
+	type testCase struct {
+		exp  map[string][]brokenlinks.Broken
+		opts brokenlinks.Options
+	}
+	var listCase = []testCase{{
+		opts: brokenlinks.Options{
+			Url: testUrl,
+		},
+		exp: map[string][]brokenlinks.Broken{
+			testUrl: {{
+				Link:    testUrl + `/broken1`,
+				Text:    `Broken 1`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    7,
+				Column:  5,
+				Count:   1,
+			}, {
+				Link:    testUrl + `/broken2.png`,
+				Text:    `Broken 2`,
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    21,
+				Column:  5,
+				Count:   1,
+			}, {
+				Link:    testUrl + `/code1`,
+				Text:    `Inside pre`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    18,
+				Column:  7,
+				Count:   1,
+			}, {
+				Link:    testUrl + `/code2`,
+				Text:    `example`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    20,
+				Column:  18,
+				Count:   1,
+			}},
+		},
+	}, {
+		opts: brokenlinks.Options{
+			Url:      testUrl,
+			SkipCode: true,
+		},
+		exp: map[string][]brokenlinks.Broken{
+			testUrl: {{
+				Link:    testUrl + `/broken1`,
+				Text:    `Broken 1`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    7,
+				Column:  5,
+				Count:   1,
+			}, {
+				Link:    testUrl + `/broken2.png`,
+				Text:    `Broken 2`,
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    21,
+				Column:  5,
+				Count:   1,
+			}},
+		},
+	}}
+
+	for _, tcase := range listCase {
+		var got, err = brokenlinks.Scan(tcase.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.Assert(t, `SkipCode=`+strconv.FormatBool(tcase.opts.SkipCode),
+			tcase.exp, got.BrokenLinks)
+	}
+}
+
+func TestBrokenlinks_cacheTTL(t *testing.T) {
+	var orgCacheFile = internal.CacheFile
+	var cacheFile = filepath.Join(t.TempDir(), `cache.json`)
+	t.Cleanup(func() {
+		internal.CacheFile = orgCacheFile
+	})
+	internal.CacheFile = func() (string, error) {
+		return cacheFile, nil
+	}
+
+	const testUrl = `http://` + testAddress + `/page2`
+	const externalUrl = `http://` + testExternalAddress + `/page2`
+
+	var now = time.Now().UTC()
+
+	type testCase struct {
+		desc      string
+		checkedAt time.Time
+		exp       []brokenlinks.Broken
+		opts      brokenlinks.Options
+	}
+	var listCase = []testCase{{
+		desc:      `With failed link not expired`,
+		checkedAt: now.Add(-1 * time.Hour),
+		opts: brokenlinks.Options{
+			Url:          testUrl,
+			CacheFailTTL: 2 * time.Hour,
+		},
+		exp: []brokenlinks.Broken{{
+			Link:    externalUrl,
+			Text:    `External URL page2`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    13,
+			Column:  5,
+			Count:   1,
+		}},
+	}, {
+		desc:      `With failed link expired`,
+		checkedAt: now.Add(-3 * time.Hour),
+		opts: brokenlinks.Options{
+			Url:          testUrl,
+			CacheFailTTL: 2 * time.Hour,
+		},
+	}, {
+		desc:      `With RefreshCache`,
+		checkedAt: now.Add(-1 * time.Hour),
+		opts: brokenlinks.Options{
+			Url:          testUrl,
+			CacheFailTTL: 2 * time.Hour,
+			RefreshCache: true,
+		},
+	}}
+
+	for _, tcase := range listCase {
+		var cache = jarink.Cache{
+			ScannedLinks: map[string]*jarink.ScannedLink{
+				externalUrl: {
+					CheckedAt:    tcase.checkedAt,
+					Url:          externalUrl,
+					ResponseCode: http.StatusNotFound,
+				},
+			},
+		}
+		var content, err = json.Marshal(&cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(cacheFile, content, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result *brokenlinks.Result
+		result, err = brokenlinks.Scan(tcase.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got = result.BrokenLinks[testUrl]
+		var exp = append([]brokenlinks.Broken{{
+			Link:    `http://` + testAddress + `/broken.png`,
+			Element: `img@src`,
+			Code:    http.StatusNotFound,
+			Line:    7,
+			Column:  5,
+			Count:   1,
+		}, {
+			Link:    testUrl + `/broken/relative`,
+			Text:    `broken relative link`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    10,
+			Column:  5,
+			Count:   2,
+		}, {
+			Link:    testUrl + `/broken2.png`,
+			Text:    `Broken image 2`,
+			Element: `img@src`,
+			Code:    http.StatusNotFound,
+			Line:    8,
+			Column:  5,
+			Count:   1,
+		}}, tcase.exp...)
+		test.Assert(t, tcase.desc, exp, got)
+	}
+}
+
+func TestScan_cacheFile(t *testing.T) {
+	const testUrl = `http://` + testAddress + `/page2`
+	const externalUrl = `http://` + testExternalAddress + `/page2`
+
+	var cacheFile = filepath.Join(t.TempDir(), `cache.db`)
+	var opts = brokenlinks.Options{
+		Url:       testUrl,
+		CacheFile: cacheFile,
+	}
+	var _, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cache *jarink.BoltCache
+	cache, err = jarink.OpenBoltCache(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cache.Close()
+	})
+
+	var got *jarink.ScannedLink
+	got, err = cache.Get(externalUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatalf(`expecting %s in cache, got nil`, externalUrl)
+	}
+	test.Assert(t, `ResponseCode`, http.StatusOK, got.ResponseCode)
+}
+
+func TestScan_diskDir(t *testing.T) {
+	const testUrl = `http://` + testAddress
+
+	var exp, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:          testUrl,
+		IgnoreStatus: `403`,
+		Insecure:     true,
+		Cache:        jarink.NewMemoryCache(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diskDir = t.TempDir()
+	var got = map[string][]brokenlinks.Broken{}
+	var opts = brokenlinks.Options{
+		Url:           testUrl,
+		IgnoreStatus:  `403`,
+		DiskDir:       diskDir,
+		Cache:         jarink.NewMemoryCache(),
+		MaxConcurrent: 2,
+		Insecure:      true,
+		OnBroken: func(page string, broken brokenlinks.Broken) {
+			got[page] = append(got[page], broken)
+		},
+	}
+	var result *brokenlinks.Result
+	result, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `result.BrokenLinks`, 0, len(result.BrokenLinks))
+
+	for _, listBroken := range got {
+		slices.SortFunc(listBroken, func(a, b brokenlinks.Broken) int {
+			return strings.Compare(a.Link, b.Link)
+		})
+	}
+	test.Assert(t, `OnBroken`, exp.BrokenLinks, got)
+
+	var listEntry []os.DirEntry
+	listEntry, err = os.ReadDir(diskDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `files in DiskDir`, 0, len(listEntry))
+}
+
+// TestScan_diskDirIgnoreStatus test the link with ignored status, linked
+// from more than one page, when the scanned links stored on disk.
+// The link is fetched once and recorded as passed, so its not reported as
+// broken.
+func TestScan_diskDirIgnoreStatus(t *testing.T) {
+	var listPage = map[string]string{
+		`/`: `<html><body><a href="/page">Page</a>` +
+			`<a href="/forbidden">Forbidden</a></body></html>`,
+		`/page`: `<html><body><a href="/forbidden">Forbidden</a>` +
+			`</body></html>`,
+	}
+	var nForbidden atomic.Int32
+	var mux = http.NewServeMux()
+	mux.HandleFunc(`/forbidden`, func(resp http.ResponseWriter, _ *http.Request) {
+		nForbidden.Add(1)
+		resp.WriteHeader(http.StatusForbidden)
+	})
+	mux.HandleFunc(`/`, func(resp http.ResponseWriter, req *http.Request) {
+		var body, ok = listPage[req.URL.Path]
+		if !ok {
+			http.NotFound(resp, req)
+			return
+		}
+		resp.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+		_, _ = resp.Write([]byte(body))
+	})
+	var srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var result, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:           srv.URL,
+		IgnoreStatus:  `403`,
+		DiskDir:       t.TempDir(),
+		NoCache:       true,
+		MaxConcurrent: 1,
+		Graph:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `BrokenLinks`, 0, len(result.BrokenLinks))
+	test.Assert(t, `number of request to /forbidden`, int32(1), nForbidden.Load())
+
+	// The graph report the real status of ignored link.
+	var gotStatus int
+	for _, node := range result.Graph.Nodes {
+		if node.Url == srv.URL+`/forbidden` {
+			gotStatus = node.Status
+		}
+	}
+	test.Assert(t, `Graph: /forbidden status`, http.StatusForbidden,
+		gotStatus)
+}
+
+// TestScan_diskDirWaiting test the broken link that found in more than
+// one page while its being scanned, where the pages that waiting for its
+// status are stored on disk.
+func TestScan_diskDirWaiting(t *testing.T) {
+	var listPage = map[string]string{
+		`/`: `<html><body><a href="/slow">Slow</a>` +
+			`<a href="/a">A</a><a href="/b">B</a></body></html>`,
+		`/a`: `<html><body><a href="/slow">Slow</a></body></html>`,
+		`/b`: `<html><body><a href="/slow">Slow</a></body></html>`,
+	}
+	var mux = http.NewServeMux()
+	mux.HandleFunc(`/slow`, func(resp http.ResponseWriter, req *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		http.NotFound(resp, req)
+	})
+	mux.HandleFunc(`/`, func(resp http.ResponseWriter, req *http.Request) {
+		var body, ok = listPage[req.URL.Path]
+		if !ok {
+			http.NotFound(resp, req)
+			return
+		}
+		resp.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+		_, _ = resp.Write([]byte(body))
+	})
+	var srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var got = map[string][]brokenlinks.Broken{}
+	var _, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     srv.URL,
+		DiskDir: t.TempDir(),
+		NoCache: true,
+		OnBroken: func(page string, broken brokenlinks.Broken) {
+			got[page] = append(got[page], broken)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Broken{}
+	for _, page := range []string{srv.URL, srv.URL + `/a`, srv.URL + `/b`} {
+		exp[page] = []brokenlinks.Broken{{
+			Link:    srv.URL + `/slow`,
+			Text:    `Slow`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    1,
+			Column:  13,
+			Count:   1,
+		}}
+	}
+	test.Assert(t, `OnBroken`, exp, got)
 }
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"slices"
+)
+
+// DiffResult contains the differences of broken links between two
+// results, grouped by page.
+type DiffResult struct {
+	// New contains the links that are broken in the new result but not
+	// in the old result.
+	New map[string][]Broken `json:"new"`
+
+	// Fixed contains the links that are broken in the old result but
+	// not in the new result.
+	Fixed map[string][]Broken `json:"fixed"`
+
+	// Still contains the links that are broken in both results.
+	Still map[string][]Broken `json:"still"`
+}
+
+// Diff compare the old and new result and return the newly broken,
+// fixed, and still broken links per page.
+// The link is compared by its page and [Broken.Link].
+func Diff(oldResult, newResult *Result) (diff *DiffResult) {
+	diff = &DiffResult{
+		New:   map[string][]Broken{},
+		Fixed: map[string][]Broken{},
+		Still: map[string][]Broken{},
+	}
+	if oldResult == nil {
+		oldResult = &Result{}
+	}
+	if newResult == nil {
+		newResult = &Result{}
+	}
+
+	for page, listNew := range newResult.BrokenLinks {
+		var listOld = oldResult.BrokenLinks[page]
+		for _, broken := range listNew {
+			if containsLink(listOld, broken.Link) {
+				diff.Still[page] = append(diff.Still[page], broken)
+			} else {
+				diff.New[page] = append(diff.New[page], broken)
+			}
+		}
+	}
+	for page, listOld := range oldResult.BrokenLinks {
+		var listNew = newResult.BrokenLinks[page]
+		for _, broken := range listOld {
+			if !containsLink(listNew, broken.Link) {
+				diff.Fixed[page] = append(diff.Fixed[page], broken)
+			}
+		}
+	}
+
+	sortBrokenLinks(diff.New)
+	sortBrokenLinks(diff.Fixed)
+	sortBrokenLinks(diff.Still)
+	return diff
+}
+
+func containsLink(listBroken []Broken, link string) bool {
+	return slices.ContainsFunc(listBroken, func(broken Broken) bool {
+		return broken.Link == link
+	})
+}
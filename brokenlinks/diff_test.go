@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"net/http"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestDiff(t *testing.T) {
+	var oldResult = &brokenlinks.Result{
+		BrokenLinks: map[string][]brokenlinks.Broken{
+			`/page1`: {{
+				Link: `/fixed`,
+				Code: http.StatusNotFound,
+			}, {
+				Link: `/still`,
+				Code: http.StatusNotFound,
+			}},
+			`/page2`: {{
+				Link: `/removed`,
+				Code: http.StatusForbidden,
+			}},
+		},
+	}
+	var newResult = &brokenlinks.Result{
+		BrokenLinks: map[string][]brokenlinks.Broken{
+			`/page1`: {{
+				Link: `/still`,
+				Code: http.StatusGone,
+			}, {
+				Link: `/new`,
+				Code: http.StatusNotFound,
+			}},
+			`/page3`: {{
+				Link: `/fixed`,
+				Code: http.StatusNotFound,
+			}},
+		},
+	}
+
+	var exp = &brokenlinks.DiffResult{
+		New: map[string][]brokenlinks.Broken{
+			`/page1`: {{
+				Link: `/new`,
+				Code: http.StatusNotFound,
+			}},
+			`/page3`: {{
+				Link: `/fixed`,
+				Code: http.StatusNotFound,
+			}},
+		},
+		Fixed: map[string][]brokenlinks.Broken{
+			`/page1`: {{
+				Link: `/fixed`,
+				Code: http.StatusNotFound,
+			}},
+			`/page2`: {{
+				Link: `/removed`,
+				Code: http.StatusForbidden,
+			}},
+		},
+		Still: map[string][]brokenlinks.Broken{
+			`/page1`: {{
+				Link: `/still`,
+				Code: http.StatusGone,
+			}},
+		},
+	}
+
+	var got = brokenlinks.Diff(oldResult, newResult)
+	test.Assert(t, `Diff`, exp, got)
+}
@@ -0,0 +1,361 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"git.sr.ht/~shulhan/jarink"
+)
+
+// pageLink contains the raw link found inside the HTML page.
+type pageLink struct {
+	// value of the attribute href or src.
+	value string
+
+	// text of the anchor or alternate text of image.
+	text string
+
+	// line and column of the element in the HTML source, start from 1.
+	line   int
+	column int
+
+	kind atom.Atom
+
+	// inCode true if the link is inside the "code" or "pre" element.
+	inCode bool
+
+	// inNav true if the link is inside the "nav" element.
+	inNav bool
+}
+
+// elementOf return the element and attribute name where the link with
+// specific kind found, for example "a@href" for anchor.
+func elementOf(kind atom.Atom) string {
+	switch kind {
+	case atom.A:
+		return `a@href`
+	case atom.Img:
+		return `img@src`
+	case atom.Link:
+		return `link@href`
+	}
+	return ``
+}
+
+// kindOf return the kind of link from the element name returned by
+// [elementOf].
+func kindOf(element string) atom.Atom {
+	switch element {
+	case `a@href`:
+		return atom.A
+	case `img@src`:
+		return atom.Img
+	case `link@href`:
+		return atom.Link
+	}
+	return 0
+}
+
+// toPageLinks convert the list of pageLink into list of
+// [jarink.PageLink], for storing in the cache.
+func toPageLinks(listLink []pageLink) (list []jarink.PageLink) {
+	list = make([]jarink.PageLink, 0, len(listLink))
+	for _, link := range listLink {
+		list = append(list, jarink.PageLink{
+			Value:   link.value,
+			Text:    link.text,
+			Element: elementOf(link.kind),
+			Line:    link.line,
+			Column:  link.column,
+			InCode:  link.inCode,
+		})
+	}
+	return list
+}
+
+// fromPageLinks convert the list of [jarink.PageLink] from the cache into
+// list of pageLink.
+func fromPageLinks(list []jarink.PageLink) (listLink []pageLink) {
+	listLink = make([]pageLink, 0, len(list))
+	for _, link := range list {
+		listLink = append(listLink, pageLink{
+			value:  link.Value,
+			text:   link.Text,
+			kind:   kindOf(link.Element),
+			line:   link.Line,
+			column: link.Column,
+			inCode: link.InCode,
+		})
+	}
+	return listLink
+}
+
+// List of markers to ignore links inside the HTML page.
+const (
+	// ignoreAttr the attribute in the element to ignore the link in
+	// the element and its children.
+	ignoreAttr = `data-jarink-ignore`
+
+	// ignoreClass the class name in the element to ignore the link in
+	// the element and its children.
+	ignoreClass = `jarink-ignore`
+
+	// ignoreCommentStart and ignoreCommentEnd the content of HTML
+	// comments to ignore the links between them.
+	ignoreCommentStart = `jarink:ignore-start`
+	ignoreCommentEnd   = `jarink:ignore-end`
+)
+
+// openElement the element that has been opened but not closed yet.
+type openElement struct {
+	name      string
+	isIgnored bool
+	isCode    bool
+	isNav     bool
+}
+
+// extractLinks parse the HTML content and return list of link on the
+// anchor href and image src, ordered by their position in the content.
+//
+// The links inside the element with attribute [ignoreAttr] or class
+// [ignoreClass], or between comments [ignoreCommentStart] and
+// [ignoreCommentEnd] are not returned.
+// The links inside the "code" and "pre" elements are marked with
+// [pageLink.inCode], and the links inside the "nav" element are marked
+// with [pageLink.inNav].
+//
+// In the same pass, the canonical link, robots "noindex", and description
+// of page are returned in meta, so the page does not need to be parsed
+// again by [extractMeta] unless the other fields are needed.
+func extractLinks(content []byte) (listLink []pageLink, meta pageMeta) {
+	var (
+		tokenizer = html.NewTokenizer(bytes.NewReader(content))
+
+		// listOpen contains the stack of opened elements.
+		listOpen []openElement
+
+		// anchor point to the index of the last anchor in listLink,
+		// for collecting its text.
+		anchor = -1
+		line   = 1
+		column = 1
+
+		// ignoreComment the number of opened ignoreCommentStart.
+		ignoreComment int
+
+		text strings.Builder
+	)
+
+	var closeAnchor = func() {
+		if anchor < 0 {
+			return
+		}
+		var anchorText = strings.Join(strings.Fields(text.String()), ` `)
+		if anchorText != `` {
+			listLink[anchor].text = anchorText
+		}
+		anchor = -1
+		text.Reset()
+	}
+
+	var isIgnored = func() bool {
+		if ignoreComment > 0 {
+			return true
+		}
+		for _, elOpen := range listOpen {
+			if elOpen.isIgnored {
+				return true
+			}
+		}
+		return false
+	}
+
+	var isInCode = func() bool {
+		for _, elOpen := range listOpen {
+			if elOpen.isCode {
+				return true
+			}
+		}
+		return false
+	}
+
+	var isInNav = func() bool {
+		for _, elOpen := range listOpen {
+			if elOpen.isNav {
+				return true
+			}
+		}
+		return false
+	}
+
+	for {
+		var (
+			tokenType = tokenizer.Next()
+			startLine = line
+			startCol  = column
+		)
+		line, column = advancePosition(tokenizer.Raw(), line, column)
+
+		switch tokenType {
+		case html.ErrorToken:
+			// The tokenizer stop on io.EOF, since reading from
+			// bytes.Reader never fail.
+			closeAnchor()
+			return listLink, meta
+
+		case html.CommentToken:
+			var comment = strings.TrimSpace(string(tokenizer.Text()))
+			switch comment {
+			case ignoreCommentStart:
+				ignoreComment++
+			case ignoreCommentEnd:
+				if ignoreComment > 0 {
+					ignoreComment--
+				}
+			}
+
+		case html.TextToken:
+			if anchor >= 0 {
+				text.Write(tokenizer.Text())
+				text.WriteByte(' ')
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			var (
+				token     = tokenizer.Token()
+				elIgnored = hasIgnoreMarker(token)
+				isCode    = token.DataAtom == atom.Code ||
+					token.DataAtom == atom.Pre
+			)
+			var isOpen = tokenType == html.StartTagToken &&
+				!isVoidElement(token.DataAtom)
+			if isOpen {
+				listOpen = append(listOpen, openElement{
+					name:      token.Data,
+					isIgnored: elIgnored,
+					isCode:    isCode,
+					isNav:     token.DataAtom == atom.Nav,
+				})
+			}
+			elIgnored = elIgnored || isIgnored()
+			isCode = isCode || isInCode()
+			var isNav = isInNav()
+
+			switch token.DataAtom {
+			case atom.Link, atom.Meta:
+				meta.parseHeadToken(token)
+
+			case atom.A:
+				closeAnchor()
+				if elIgnored {
+					continue
+				}
+				var val, ok = attrValue(token.Attr, `href`)
+				if !ok {
+					continue
+				}
+				listLink = append(listLink, pageLink{
+					value:  val,
+					kind:   atom.A,
+					line:   startLine,
+					column: startCol,
+					inCode: isCode,
+					inNav:  isNav,
+				})
+				if tokenType == html.StartTagToken {
+					anchor = len(listLink) - 1
+				}
+
+			case atom.Img:
+				var alt, _ = attrValue(token.Attr, `alt`)
+				if anchor >= 0 {
+					text.WriteString(alt)
+					text.WriteByte(' ')
+				}
+				if elIgnored {
+					continue
+				}
+				var val, ok = attrValue(token.Attr, `src`)
+				if !ok {
+					continue
+				}
+				listLink = append(listLink, pageLink{
+					value:  val,
+					text:   strings.Join(strings.Fields(alt), ` `),
+					kind:   atom.Img,
+					line:   startLine,
+					column: startCol,
+					inCode: isCode,
+				})
+			}
+
+		case html.EndTagToken:
+			var rawName, _ = tokenizer.TagName()
+			var name = string(rawName)
+			if name == `a` {
+				closeAnchor()
+			}
+			// Close the element and all of its unclosed children.
+			var x = len(listOpen) - 1
+			for ; x >= 0; x-- {
+				if listOpen[x].name == name {
+					listOpen = listOpen[:x]
+					break
+				}
+			}
+		}
+	}
+}
+
+// hasIgnoreMarker return true if the element has attribute [ignoreAttr]
+// or class [ignoreClass].
+func hasIgnoreMarker(token html.Token) bool {
+	if _, ok := attrValue(token.Attr, ignoreAttr); ok {
+		return true
+	}
+	var class, _ = attrValue(token.Attr, `class`)
+	return slices.Contains(strings.Fields(class), ignoreClass)
+}
+
+// isVoidElement return true if the element cannot have any children, so
+// its does not have end tag.
+func isVoidElement(name atom.Atom) bool {
+	switch name {
+	case atom.Area, atom.Base, atom.Br, atom.Col, atom.Embed, atom.Hr,
+		atom.Img, atom.Input, atom.Link, atom.Meta, atom.Param,
+		atom.Source, atom.Track, atom.Wbr:
+		return true
+	}
+	return false
+}
+
+// attrValue return the value of the first attribute with key.
+func attrValue(listAttr []html.Attribute, key string) (val string, ok bool) {
+	for _, attr := range listAttr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return ``, false
+}
+
+// advancePosition return the line and column after reading the raw bytes
+// from position line and column.
+// The column is counted in runes.
+func advancePosition(raw []byte, line, column int) (int, int) {
+	var idx = bytes.LastIndexByte(raw, '\n')
+	if idx < 0 {
+		return line, column + utf8.RuneCount(raw)
+	}
+	line += bytes.Count(raw, []byte{'\n'})
+	column = 1 + utf8.RuneCount(raw[idx+1:])
+	return line, column
+}
@@ -0,0 +1,623 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// fixContextLines the number of unchanged lines around the changes in
+// the unified diff.
+const fixContextLines = 3
+
+var (
+	fixHTMLAttrRx    = regexp.MustCompile(`(?i)\b(?:href|src)[ \t]*=[ \t]*(?:"([^"]*)"|'([^']*)')`)
+	fixHTMLCodeRx    = regexp.MustCompile(`(?i)<!--|<(?:pre|code)(?:[\s>]|$)`)
+	fixMarkdownRx    = regexp.MustCompile(`\]\([ \t]*<?([^)\s>]+)`)
+	fixMarkdownRefRx = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*<?([^\s>]+)`)
+)
+
+// FixOptions define the options for [Fix].
+type FixOptions struct {
+	// Replacements map the old link to the new link.
+	// The link can be the URL that start with Url, the path to the file
+	// inside Dir as reported by scanning local directory, or the
+	// external URL that replaced as is.
+	// See [Result.Replacements] and [LoadReplacements] to create it.
+	Replacements map[string]string
+
+	// Dir the directory that contains the HTML and Markdown files to be
+	// fixed.
+	Dir string
+
+	// Url the base URL of website where the files in Dir are served,
+	// for example "https://web.tld".
+	// It is required if the Replacements contains the links from
+	// scanning the website.
+	Url string
+
+	// DryRun if true the files are not modified.
+	DryRun bool
+}
+
+// FixResult contains the changes made by [Fix].
+type FixResult struct {
+	// Diff the changes in unified diff format.
+	Diff string
+
+	// Files contains the path of files that has been, or will be if
+	// DryRun is true, modified.
+	Files []string
+
+	// Count the number of links replaced.
+	Count int
+}
+
+// LoadReplacements load the map of old link to new link from JSON file.
+// The file can be the result of brokenlinks, where the broken links are
+// replaced with its first suggestion, or the redirect map with the
+// following format,
+//
+//	{
+//		"<old link>": "<new link>",
+//		...
+//	}
+func LoadReplacements(file string) (replacements map[string]string, err error) {
+	var logp = `LoadReplacements`
+
+	var content []byte
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(content, &fields)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %s: %w`, logp, file, err)
+	}
+	if _, ok := fields[`broken_links`]; ok {
+		var result = newResult()
+		err = json.Unmarshal(content, result)
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %s: %w`, logp, file, err)
+		}
+		return result.Replacements(), nil
+	}
+
+	err = json.Unmarshal(content, &replacements)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %s: %w`, logp, file, err)
+	}
+	return replacements, nil
+}
+
+// Replacements return the map of broken link to its first
+// [Broken.Suggestions].
+// The broken link without suggestions is not included.
+func (result *Result) Replacements() (replacements map[string]string) {
+	replacements = map[string]string{}
+	for _, listBroken := range result.BrokenLinks {
+		for _, broken := range listBroken {
+			if len(broken.Suggestions) != 0 {
+				replacements[broken.Link] = broken.Suggestions[0]
+			}
+		}
+	}
+	return replacements
+}
+
+// Fix replace the links inside the HTML and Markdown files in
+// [FixOptions.Dir] based on [FixOptions.Replacements].
+// The links are matched after resolved relative to the file, and the new
+// link is written using the same form as the old link: full URL,
+// absolute path, or path relative to the file.
+// The query and fragment in the old link are kept.
+func Fix(opts FixOptions) (result *FixResult, err error) {
+	var logp = `Fix`
+
+	var fixer = &fixer{
+		opts:     opts,
+		sitePath: map[string]string{},
+		external: map[string]string{},
+	}
+	fixer.opts.Url = strings.TrimSuffix(opts.Url, `/`)
+	for oldLink, newLink := range opts.Replacements {
+		var oldPath = fixer.toSitePath(oldLink)
+		if oldPath == `` {
+			fixer.external[oldLink] = newLink
+			continue
+		}
+		fixer.sitePath[oldPath] = newLink
+	}
+
+	var listFile []string
+	err = filepath.WalkDir(opts.Dir,
+		func(file string, entry fs.DirEntry, errWalk error) error {
+			if errWalk != nil {
+				return errWalk
+			}
+			if entry.IsDir() {
+				if file != opts.Dir &&
+					strings.HasPrefix(entry.Name(), `.`) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if fixFormatOf(file) != `` {
+				listFile = append(listFile, file)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	slices.Sort(listFile)
+
+	result = &FixResult{}
+	var diff strings.Builder
+	for _, file := range listFile {
+		var n int
+		n, err = fixer.fixFile(file, &diff)
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, err)
+		}
+		if n == 0 {
+			continue
+		}
+		result.Files = append(result.Files, file)
+		result.Count += n
+	}
+	result.Diff = diff.String()
+	return result, nil
+}
+
+// fixer contains the state for [Fix].
+type fixer struct {
+	// sitePath map the path of old link, relative to the root of
+	// website, to the new link.
+	sitePath map[string]string
+
+	// external map the old link that is not inside the website to the
+	// new link.
+	external map[string]string
+
+	opts FixOptions
+}
+
+// fixFormatOf return the format of file to be fixed based on its
+// extension, either "html" or "markdown", or empty if the file is not
+// fixable.
+func fixFormatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case `.html`, `.htm`:
+		return `html`
+	case `.md`, `.markdown`:
+		return `markdown`
+	}
+	return ``
+}
+
+// fixFile replace the links in the file, write the unified diff of the
+// changes into diff, and write the file if not DryRun.
+// It return the number of links replaced.
+func (fixer *fixer) fixFile(file string, diff *strings.Builder) (
+	n int, err error,
+) {
+	var content []byte
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	// The last line break is removed, so the last empty line is not
+	// part of the diff.
+	var (
+		text, hasEOL = strings.CutSuffix(string(content), "\n")
+		pagePath     = fixer.toSitePath(file)
+		isHTML       = fixFormatOf(file) == `html`
+		oldLines     = strings.Split(text, "\n")
+		newLines     = make([]string, len(oldLines))
+		fence        string
+		indent       markdownIndent
+
+		// closing the end of HTML comment or code element, that
+		// started on previous line, in HTML file.
+		closing string
+
+		// inComment true if the line is inside the HTML comment, that
+		// started on previous line, in Markdown file.
+		inComment bool
+	)
+	for x, line := range oldLines {
+		newLines[x] = line
+		if isHTML {
+			var lineClosing = closing
+			var mask = func(line string) string {
+				var masked, _ = maskHtmlCode(line, lineClosing)
+				return masked
+			}
+			newLines[x], n = fixer.fixLine(pagePath, line, mask,
+				fixHTMLAttrRx, true, n)
+			_, closing = maskHtmlCode(line, closing)
+			continue
+		}
+
+		var trimmed = strings.TrimSpace(line)
+		if fence != `` {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ``
+			}
+			indent.blank()
+			continue
+		}
+
+		var lineInComment = inComment
+		var masked string
+		masked, inComment = maskHtmlComments(maskCodeSpans(line), inComment)
+		if strings.TrimSpace(masked) == `` {
+			indent.blank()
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") ||
+			strings.HasPrefix(trimmed, `~~~`) {
+			fence = trimmed[:3]
+			indent.blank()
+			continue
+		}
+		if indent.isCode(line) {
+			continue
+		}
+
+		var mask = func(line string) string {
+			var masked, _ = maskHtmlComments(maskCodeSpans(line),
+				lineInComment)
+			return alignMask(line, masked)
+		}
+		newLines[x], n = fixer.fixLine(pagePath, newLines[x], mask,
+			fixMarkdownRefRx, false, n)
+		newLines[x], n = fixer.fixLine(pagePath, newLines[x], mask,
+			fixMarkdownRx, false, n)
+		newLines[x], n = fixer.fixLine(pagePath, newLines[x], mask,
+			fixHTMLAttrRx, true, n)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	// The file name in diff is relative to the Dir, so the diff can be
+	// applied inside the Dir using "patch -p1" or "git apply".
+	var name, errRel = filepath.Rel(fixer.opts.Dir, file)
+	if errRel != nil {
+		name = file
+	}
+	writeUnifiedDiff(diff, filepath.ToSlash(name), oldLines, newLines,
+		hasEOL)
+
+	if fixer.opts.DryRun {
+		return n, nil
+	}
+	var fi os.FileInfo
+	fi, err = os.Stat(file)
+	if err != nil {
+		return 0, err
+	}
+	text = strings.Join(newLines, "\n")
+	if hasEOL {
+		text += "\n"
+	}
+	err = internal.WriteFileAtomic(file, []byte(text), fi.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// fixLine replace the links in the line captured by the sub matches in
+// rx.
+// The rx is matched against the line returned by mask, where the code and
+// comments are replaced with spaces, so the links inside them are not
+// replaced.
+// If isHTML is true, the link is unescaped before and escaped after
+// replaced.
+// It return the new line and n plus the number of links replaced.
+func (fixer *fixer) fixLine(pagePath, line string,
+	mask func(string) string, rx *regexp.Regexp, isHTML bool, n int,
+) (string, int) {
+	var listMatch = rx.FindAllStringSubmatchIndex(mask(line), -1)
+	if len(listMatch) == 0 {
+		return line, n
+	}
+	var (
+		sb   strings.Builder
+		last int
+	)
+	for _, m := range listMatch {
+		for x := 2; x < len(m); x += 2 {
+			if m[x] < 0 {
+				continue
+			}
+			var value = line[m[x]:m[x+1]]
+			if isHTML {
+				value = html.UnescapeString(value)
+			}
+			var newValue, ok = fixer.replace(pagePath, value)
+			if !ok {
+				continue
+			}
+			if isHTML {
+				newValue = html.EscapeString(newValue)
+			}
+			sb.WriteString(line[last:m[x]])
+			sb.WriteString(newValue)
+			last = m[x+1]
+			n++
+		}
+	}
+	sb.WriteString(line[last:])
+	return sb.String(), n
+}
+
+// maskHtmlCode replace each byte inside the HTML comments and the
+// elements "pre" and "code" with space.
+// The closing parameter is the end of comment, "-->", or the end tag, for
+// example "</pre", of element that started on previous line.
+// It return the masked line and the closing of comment or element that is
+// not closed in the line.
+func maskHtmlCode(line, closing string) (string, string) {
+	if closing == `` && !fixHTMLCodeRx.MatchString(line) {
+		return line, ``
+	}
+	var (
+		masked = []byte(line)
+		pos    int
+	)
+	for pos < len(line) {
+		var start = pos
+		if closing == `` {
+			var loc = fixHTMLCodeRx.FindStringIndex(line[pos:])
+			if loc == nil {
+				break
+			}
+			start = pos + loc[0]
+			pos += loc[1]
+			var tag = line[start:pos]
+			if tag == `<!--` {
+				closing = `-->`
+			} else {
+				tag = strings.TrimRight(tag[1:], " \t\r\n\f>")
+				closing = `</` + strings.ToLower(tag)
+			}
+		}
+		var end = indexFold(line[pos:], closing)
+		if end < 0 {
+			fillSpace(masked[start:])
+			return string(masked), closing
+		}
+		end += pos + len(closing)
+		if closing != `-->` {
+			var gt = strings.IndexByte(line[end:], '>')
+			if gt >= 0 {
+				end += gt + 1
+			}
+		}
+		fillSpace(masked[start:end])
+		pos = end
+		closing = ``
+	}
+	return string(masked), ``
+}
+
+// indexFold return the index of first ASCII sub string sub in s, ignoring
+// the case, or -1 if not found.
+func indexFold(s, sub string) int {
+	for x := 0; x+len(sub) <= len(s); x++ {
+		if strings.EqualFold(s[x:x+len(sub)], sub) {
+			return x
+		}
+	}
+	return -1
+}
+
+// fillSpace replace each byte in b with space.
+func fillSpace(b []byte) {
+	for x := range b {
+		b[x] = ' '
+	}
+}
+
+// alignMask return the line where the rune replaced with space in masked,
+// as returned by [maskCodeSpans] and [maskHtmlComments], is replaced with
+// spaces as many as its length in bytes, so the index of masked line
+// match with the index of line.
+func alignMask(line, masked string) string {
+	if line == masked {
+		return line
+	}
+	var (
+		sb          strings.Builder
+		maskedRunes = []rune(masked)
+	)
+	for x := 0; line != ``; x++ {
+		var r, size = utf8.DecodeRuneInString(line)
+		if x < len(maskedRunes) && maskedRunes[x] == ' ' && r != ' ' {
+			sb.WriteString(strings.Repeat(` `, size))
+		} else {
+			sb.WriteString(line[:size])
+		}
+		line = line[size:]
+	}
+	return sb.String()
+}
+
+// replace return the new link for the link value inside the page, and
+// true if the link should be replaced.
+func (fixer *fixer) replace(pagePath, value string) (string, bool) {
+	var link, suffix = value, ``
+	var idx = strings.IndexAny(value, `?#`)
+	if idx >= 0 {
+		link, suffix = value[:idx], value[idx:]
+	}
+	if link == `` {
+		return ``, false
+	}
+
+	var newLink, ok = fixer.external[link]
+	if ok {
+		return newLink + suffix, true
+	}
+
+	var (
+		isUrl    = schemeRx.MatchString(link)
+		linkPath string
+	)
+	switch {
+	case isUrl:
+		if fixer.opts.Url == `` ||
+			!strings.HasPrefix(link, fixer.opts.Url) {
+			return ``, false
+		}
+		linkPath = path.Clean(`/` + strings.TrimPrefix(link,
+			fixer.opts.Url))
+	case strings.HasPrefix(link, `//`):
+		return ``, false
+	case strings.HasPrefix(link, `/`):
+		linkPath = path.Clean(link)
+	default:
+		linkPath = path.Join(path.Dir(pagePath), link)
+	}
+
+	newLink, ok = fixer.sitePath[linkPath]
+	if !ok {
+		return ``, false
+	}
+	var newPath = fixer.toSitePath(newLink)
+	switch {
+	case newPath == ``:
+		// The new link is external.
+	case isUrl:
+		newLink = fixer.opts.Url + newPath
+	case strings.HasPrefix(link, `/`):
+		newLink = newPath
+	default:
+		var rel, err = filepath.Rel(
+			filepath.FromSlash(path.Dir(pagePath)),
+			filepath.FromSlash(newPath))
+		if err != nil {
+			newLink = newPath
+		} else {
+			newLink = filepath.ToSlash(rel)
+		}
+	}
+	if newLink+suffix == value {
+		return ``, false
+	}
+	return newLink + suffix, true
+}
+
+// toSitePath return the path of link relative to the root of website,
+// start with "/", or empty string if the link is not inside the website.
+// The link can be URL that start with [FixOptions.Url] or path to the
+// file inside [FixOptions.Dir].
+func (fixer *fixer) toSitePath(link string) string {
+	if fixer.opts.Url != `` {
+		var rest, ok = strings.CutPrefix(link, fixer.opts.Url)
+		if ok && (rest == `` || rest[0] == '/') {
+			return path.Clean(`/` + rest)
+		}
+	}
+	if schemeRx.MatchString(link) {
+		return ``
+	}
+	var rel, err = filepath.Rel(fixer.opts.Dir, link)
+	if err != nil || rel == `..` ||
+		strings.HasPrefix(rel, `..`+string(filepath.Separator)) {
+		return ``
+	}
+	return path.Clean(`/` + filepath.ToSlash(rel))
+}
+
+// writeUnifiedDiff write the changes between oldLines and newLines of
+// the file in unified diff format.
+// Both oldLines and newLines must have the same number of lines, since
+// Fix only replace the links inside the line.
+// If hasEOL is false, the last line is followed by the marker
+// "\ No newline at end of file".
+func writeUnifiedDiff(
+	diff *strings.Builder, name string, oldLines, newLines []string,
+	hasEOL bool,
+) {
+	var listChanged []int
+	for x := range oldLines {
+		if oldLines[x] != newLines[x] {
+			listChanged = append(listChanged, x)
+		}
+	}
+	if len(listChanged) == 0 {
+		return
+	}
+
+	fmt.Fprintf(diff, "--- a/%s\n+++ b/%s\n", name, name)
+
+	var last = len(oldLines) - 1
+
+	var x int
+	for x < len(listChanged) {
+		var (
+			start = max(0, listChanged[x]-fixContextLines)
+			end   = min(len(oldLines), listChanged[x]+fixContextLines+1)
+		)
+		// Merge the next changes that overlap with current hunk.
+		x++
+		for x < len(listChanged) &&
+			listChanged[x]-fixContextLines <= end {
+			end = min(len(oldLines), listChanged[x]+fixContextLines+1)
+			x++
+		}
+
+		var hunk bytes.Buffer
+		var writeLine = func(prefix string, y int, line string) {
+			hunk.WriteString(prefix + line + "\n")
+			if y == last && !hasEOL {
+				hunk.WriteString("\\ No newline at end of file\n")
+			}
+		}
+		for y := start; y < end; y++ {
+			if oldLines[y] == newLines[y] {
+				writeLine(` `, y, oldLines[y])
+				continue
+			}
+			// Write the consecutive changed lines, the old lines
+			// first and then the new lines.
+			var next = y
+			for next < end && oldLines[next] != newLines[next] {
+				next++
+			}
+			for z := y; z < next; z++ {
+				writeLine(`-`, z, oldLines[z])
+			}
+			for z := y; z < next; z++ {
+				writeLine(`+`, z, newLines[z])
+			}
+			y = next - 1
+		}
+		var lineRange = strconv.Itoa(start+1) + `,` + strconv.Itoa(end-start)
+		fmt.Fprintf(diff, "@@ -%s +%s @@\n", lineRange, lineRange)
+		diff.Write(hunk.Bytes())
+	}
+}
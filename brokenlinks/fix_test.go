@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestFix(t *testing.T) {
+	var dir = t.TempDir()
+	var err = os.CopyFS(dir, os.DirFS(`testdata/fix`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var opts = brokenlinks.FixOptions{
+		Replacements: map[string]string{
+			filepath.Join(dir, `About.html`): filepath.Join(dir,
+				`about.html`),
+			filepath.Join(dir, `install.html`): filepath.Join(dir,
+				`docs`, `install.html`),
+			`https://web.tld/abuot.html`: `https://web.tld/about.html`,
+			`http://old.tld/page`:        `https://new.tld/page`,
+		},
+		Dir:    dir,
+		Url:    `https://web.tld`,
+		DryRun: true,
+	}
+
+	var indexFile = filepath.Join(dir, `index.html`)
+	var guideFile = filepath.Join(dir, `docs`, `guide.md`)
+	var expDiff = `--- a/docs/guide.md
++++ b/docs/guide.md
+@@ -5,16 +5,16 @@
+ 
+ # Guide
+ 
+-See [install](../install.html) and [about][about].
++See [install](install.html) and [about][about].
+ 
+ ` + "```" + `
+ [inside code](../install.html)
+ ` + "```" + `
+ 
+-[about]: /About.html
++[about]: /about.html
+ 
+ Use ` + "`[install](../install.html)`" + ` <!-- [install](../install.html) --> or
+-[install](../install.html).
++[install](install.html).
+ 
+ <!--
+ [about](/About.html)
+@@ -24,4 +24,4 @@
+ 
+     [install](../install.html)
+ 
+-[install](../install.html)
+\ No newline at end of file
++[install](install.html)
+\ No newline at end of file
+--- a/index.html
++++ b/index.html
+@@ -4,16 +4,16 @@
+ -->
+ <html>
+   <body>
+-    <a href="/About.html#team">About</a>
+-    <a href="install.html">Install</a>
+-    <a href="https://web.tld/abuot.html">About with URL</a>
+-    <a href="http://old.tld/page">Old page</a>
++    <a href="/about.html#team">About</a>
++    <a href="docs/install.html">Install</a>
++    <a href="https://web.tld/about.html">About with URL</a>
++    <a href="https://new.tld/page">Old page</a>
+     <a href="/unknown.html">Unknown</a>
+     <!-- <a href="/About.html">About in comment</a> -->
+     <pre>
+ <a href="install.html">Install in pre</a>
+     </pre>
+     <code>&lt;a href="install.html"&gt;</code>
+-    <a href="install.html">Install after code</a>
++    <a href="docs/install.html">Install after code</a>
+   </body>
+ </html>
+`
+	var got *brokenlinks.FixResult
+	got, err = brokenlinks.Fix(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exp = &brokenlinks.FixResult{
+		Diff:  expDiff,
+		Files: []string{guideFile, indexFile},
+		Count: 9,
+	}
+	test.Assert(t, `DryRun`, exp, got)
+
+	var content []byte
+	content, err = os.ReadFile(indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var orig []byte
+	orig, err = os.ReadFile(`testdata/fix/index.html`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `DryRun: unchanged`, string(orig), string(content))
+
+	opts.DryRun = false
+	_, err = brokenlinks.Fix(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = brokenlinks.Fix(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `Fix again`, &brokenlinks.FixResult{}, got)
+}
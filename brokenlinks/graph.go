@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Graph contains the pages and links found during scan, where each node
+// is the page, image, or external link, and each edge is the link from
+// the page to other node.
+type Graph struct {
+	// nodeIdx the index of node in Nodes by its URL.
+	nodeIdx map[string]int
+
+	// edgeIdx the index of edge in Edges by its source and target.
+	edgeIdx map[[2]string]int
+
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode the page, image, or external link in the [Graph].
+type GraphNode struct {
+	// Url of the node, or the path of file when scanning local
+	// directory.
+	Url string `json:"url"`
+
+	// Status the HTTP status code of the node, or [StatusBadLink].
+	// Its 0 if the node is not scanned.
+	// The link with status in [Options.IgnoreStatus] has its real
+	// status.
+	Status int `json:"status"`
+
+	// Depth the minimum number of links from the root page,
+	// [Options.Url], to this node.
+	// Its -1 if the node cannot be reached from the root page.
+	Depth int `json:"depth"`
+
+	// Size of the node, derived from HTTP response Content-Length.
+	Size int64 `json:"size,omitempty"`
+
+	// ContentType of the node, from HTTP response header Content-Type.
+	ContentType string `json:"content_type,omitempty"`
+
+	// ResponseTime the duration to fetch the node, in milliseconds.
+	ResponseTime int64 `json:"response_time,omitempty"`
+
+	// IsExternal true if the node is not under the [Options.Url].
+	IsExternal bool `json:"is_external,omitempty"`
+}
+
+// GraphEdge the link from the page to other node in the [Graph].
+type GraphEdge struct {
+	// From the URL of page where the link found.
+	From string `json:"from"`
+
+	// To the URL of the link.
+	To string `json:"to"`
+
+	// Element and attribute where the link found, either "a@href" or
+	// "img@src".
+	Element string `json:"element"`
+
+	// Text of the first anchor or alternate text of image.
+	Text string `json:"text,omitempty"`
+
+	// Count number of the same link found inside the page.
+	Count int `json:"count"`
+}
+
+func newGraph() *Graph {
+	return &Graph{
+		nodeIdx: map[string]int{},
+		edgeIdx: map[[2]string]int{},
+	}
+}
+
+// node return the node by its URL, create new one if its not exist.
+func (graph *Graph) node(url string) *GraphNode {
+	var idx, ok = graph.nodeIdx[url]
+	if !ok {
+		idx = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, GraphNode{Url: url})
+		graph.nodeIdx[url] = idx
+	}
+	return &graph.Nodes[idx]
+}
+
+// addEdge add the link from linkq.parentUrl to linkq.url.
+// The same link from the same page is added only once.
+func (graph *Graph) addEdge(linkq linkQueue) {
+	var from = linkq.parent()
+	var key = [2]string{from, linkq.url}
+	if _, ok := graph.edgeIdx[key]; ok {
+		return
+	}
+	graph.node(from)
+	var node = graph.node(linkq.url)
+	node.IsExternal = linkq.isExternal
+
+	graph.edgeIdx[key] = len(graph.Edges)
+	graph.Edges = append(graph.Edges, GraphEdge{
+		From:    from,
+		To:      linkq.url,
+		Element: elementOf(linkq.kind),
+		Text:    linkq.text,
+		Count:   linkq.count,
+	})
+}
+
+// setNode set the status and size of scanned link.
+func (graph *Graph) setNode(linkq linkQueue) {
+	var node = graph.node(linkq.url)
+	node.Status = linkq.status
+	node.Size = linkq.size
+	node.ContentType = linkq.contentType
+	node.ResponseTime = linkq.responseTime.Milliseconds()
+	node.IsExternal = linkq.isExternal
+}
+
+// finalize set the depth of each node from the root, rename the URL of
+// nodes using the function rename, and sort the nodes and edges by URL.
+func (graph *Graph) finalize(root string, rename func(string) string) {
+	var listChild = map[string][]string{}
+	for _, edge := range graph.Edges {
+		listChild[edge.From] = append(listChild[edge.From], edge.To)
+	}
+
+	for x := range graph.Nodes {
+		graph.Nodes[x].Depth = -1
+	}
+	if _, ok := graph.nodeIdx[root]; ok {
+		graph.node(root).Depth = 0
+		var queue = []string{root}
+		for len(queue) != 0 {
+			var parent = graph.node(queue[0])
+			queue = queue[1:]
+			for _, url := range listChild[parent.Url] {
+				var child = graph.node(url)
+				if child.Depth >= 0 {
+					continue
+				}
+				child.Depth = parent.Depth + 1
+				queue = append(queue, url)
+			}
+		}
+	}
+
+	if rename != nil {
+		for x, node := range graph.Nodes {
+			graph.Nodes[x].Url = rename(node.Url)
+		}
+		for x, edge := range graph.Edges {
+			graph.Edges[x].From = rename(edge.From)
+			graph.Edges[x].To = rename(edge.To)
+		}
+	}
+
+	slices.SortFunc(graph.Nodes, func(a, b GraphNode) int {
+		return strings.Compare(a.Url, b.Url)
+	})
+	slices.SortFunc(graph.Edges, func(a, b GraphEdge) int {
+		var cmp = strings.Compare(a.From, b.From)
+		if cmp != 0 {
+			return cmp
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	graph.reindex()
+}
+
+// reindex build the index of nodes and edges, for example after the graph
+// loaded from the state.
+func (graph *Graph) reindex() {
+	graph.nodeIdx = make(map[string]int, len(graph.Nodes))
+	for x, node := range graph.Nodes {
+		graph.nodeIdx[node.Url] = x
+	}
+	graph.edgeIdx = make(map[[2]string]int, len(graph.Edges))
+	for x, edge := range graph.Edges {
+		graph.edgeIdx[[2]string{edge.From, edge.To}] = x
+	}
+}
+
+// WriteJSON write the graph in JSON format.
+func (graph *Graph) WriteJSON(w io.Writer) error {
+	var enc = json.NewEncoder(w)
+	enc.SetIndent(``, `  `)
+	return enc.Encode(graph)
+}
+
+// WriteDOT write the graph in the DOT language of Graphviz.
+// The node has attributes "status", "depth", "size", and "external",
+// while the edge has attributes "element", "label" (the text of link),
+// and "count".
+func (graph *Graph) WriteDOT(w io.Writer) error {
+	var bw = bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "digraph jarink {\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(bw, "\t%s [status=%d, depth=%d, size=%d,"+
+			" external=%t];\n", dotQuote(node.Url), node.Status,
+			node.Depth, node.Size, node.IsExternal)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(bw, "\t%s -> %s [element=%s, label=%s,"+
+			" count=%d];\n", dotQuote(edge.From), dotQuote(edge.To),
+			dotQuote(edge.Element), dotQuote(edge.Text), edge.Count)
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+// dotQuote return the string as quoted ID in DOT language.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// WriteGraphML write the graph in GraphML format, where the ID of node
+// is its URL.
+// The node has data "status", "depth", "size", and "external", while the
+// edge has data "element", "text", and "count".
+func (graph *Graph) WriteGraphML(w io.Writer) error {
+	var bw = bufio.NewWriter(w)
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	var listKey = [][3]string{
+		{`node`, `status`, `int`},
+		{`node`, `depth`, `int`},
+		{`node`, `size`, `long`},
+		{`node`, `external`, `boolean`},
+		{`edge`, `element`, `string`},
+		{`edge`, `text`, `string`},
+		{`edge`, `count`, `int`},
+	}
+	for _, key := range listKey {
+		fmt.Fprintf(bw, "  <key id=%q for=%q attr.name=%q attr.type=%q/>\n",
+			key[1], key[0], key[1], key[2])
+	}
+	bw.WriteString(`  <graph id="jarink" edgedefault="directed">` + "\n")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", xmlEscape(node.Url))
+		fmt.Fprintf(bw, "      <data key=\"status\">%d</data>\n", node.Status)
+		fmt.Fprintf(bw, "      <data key=\"depth\">%d</data>\n", node.Depth)
+		fmt.Fprintf(bw, "      <data key=\"size\">%d</data>\n", node.Size)
+		fmt.Fprintf(bw, "      <data key=\"external\">%t</data>\n", node.IsExternal)
+		bw.WriteString("    </node>\n")
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(bw, "    <edge source=\"%s\" target=\"%s\">\n",
+			xmlEscape(edge.From), xmlEscape(edge.To))
+		fmt.Fprintf(bw, "      <data key=\"element\">%s</data>\n", xmlEscape(edge.Element))
+		fmt.Fprintf(bw, "      <data key=\"text\">%s</data>\n", xmlEscape(edge.Text))
+		fmt.Fprintf(bw, "      <data key=\"count\">%d</data>\n", edge.Count)
+		bw.WriteString("    </edge>\n")
+	}
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+// xmlEscape return the string with XML special characters escaped.
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_graph(t *testing.T) {
+	var dir = filepath.Join(`testdata`, `local`)
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     dir,
+		NoCache: true,
+		Graph:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x := range got.Graph.Nodes {
+		// The response time is different on each scan.
+		got.Graph.Nodes[x].ResponseTime = 0
+	}
+
+	const (
+		typeHTML = `text/html; charset=utf-8`
+		typeText = `text/plain; charset=utf-8`
+	)
+	var (
+		about      = filepath.Join(dir, `about.html`)
+		guide      = filepath.Join(dir, `docs`, `guide.html`)
+		docs       = filepath.Join(dir, `docs`, `index.html`)
+		docsImage  = filepath.Join(dir, `docs`, `missing.png`)
+		index      = filepath.Join(dir, `index.html`)
+		missing    = filepath.Join(dir, `missing`)
+		missingPng = filepath.Join(dir, `missing.png`)
+	)
+	var expNodes = []brokenlinks.GraphNode{
+		{Url: about, Status: 200, Depth: 1, Size: 243,
+			ContentType: typeHTML},
+		{Url: guide, Status: 404, Depth: 2, Size: 19,
+			ContentType: typeText},
+		{Url: docs, Status: 200, Depth: 1, Size: 209,
+			ContentType: typeHTML},
+		{Url: docsImage, Status: 404, Depth: 2, Size: 19,
+			ContentType: typeText},
+		{Url: index, Status: 200, Depth: 0, Size: 284,
+			ContentType: typeHTML},
+		{Url: missing, Status: 404, Depth: 1, Size: 19,
+			ContentType: typeText},
+		{Url: missingPng, Status: 404, Depth: 1, Size: 19,
+			ContentType: typeText},
+	}
+	test.Assert(t, `Nodes`, expNodes, got.Graph.Nodes)
+
+	var expEdges = []brokenlinks.GraphEdge{
+		{From: about, To: guide, Element: `a@href`, Text: `Guide`, Count: 1},
+		{From: about, To: docs, Element: `a@href`, Text: `Docs`, Count: 1},
+		{From: about, To: index, Element: `a@href`, Text: `Home`, Count: 1},
+		{From: docs, To: about, Element: `a@href`, Text: `About`, Count: 1},
+		{From: docs, To: docsImage, Element: `img@src`, Count: 1},
+		{From: index, To: about, Element: `a@href`, Text: `About`, Count: 1},
+		{From: index, To: docs, Element: `a@href`, Text: `Docs`, Count: 1},
+		{From: index, To: missing, Element: `a@href`, Text: `Missing`, Count: 1},
+		{From: index, To: missingPng, Element: `img@src`, Text: `Missing image`, Count: 1},
+	}
+	test.Assert(t, `Edges`, expEdges, got.Graph.Edges)
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	var graph = &brokenlinks.Graph{
+		Nodes: []brokenlinks.GraphNode{{
+			Url:    `https://web.tld`,
+			Status: 200,
+			Size:   1024,
+		}, {
+			Url:        `https://other.tld/"quoted"`,
+			Status:     404,
+			Depth:      1,
+			IsExternal: true,
+		}},
+		Edges: []brokenlinks.GraphEdge{{
+			From:    `https://web.tld`,
+			To:      `https://other.tld/"quoted"`,
+			Element: `a@href`,
+			Text:    `Other`,
+			Count:   2,
+		}},
+	}
+
+	var buf bytes.Buffer
+	var err = graph.WriteDOT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exp = `digraph jarink {
+	"https://web.tld" [status=200, depth=0, size=1024, external=false];
+	"https://other.tld/\"quoted\"" [status=404, depth=1, size=0, external=true];
+	"https://web.tld" -> "https://other.tld/\"quoted\"" [element="a@href", label="Other", count=2];
+}
+`
+	test.Assert(t, `WriteDOT`, exp, buf.String())
+}
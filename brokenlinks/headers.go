@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HstsMinMaxAge the minimum value of "max-age" in header
+// "Strict-Transport-Security", in seconds, which is 180 days.
+const HstsMinMaxAge = 180 * 24 * 60 * 60
+
+// List of rules reported by security headers audit.
+const (
+	HeadersContentTypeOptions = `headers/content-type-options`
+	HeadersCookieHttpOnly     = `headers/cookie-httponly`
+	HeadersCookieSameSite     = `headers/cookie-samesite`
+	HeadersCookieSecure       = `headers/cookie-secure`
+	HeadersCspMissing         = `headers/csp-missing`
+	HeadersFrameProtection    = `headers/frame-protection`
+	HeadersHstsMaxAge         = `headers/hsts-max-age`
+	HeadersHstsMissing        = `headers/hsts-missing`
+	HeadersPermissionsPolicy  = `headers/permissions-policy-missing`
+	HeadersReferrerPolicy     = `headers/referrer-policy`
+	HeadersServerVersion      = `headers/server-version`
+)
+
+// listVersionHeader contains the response headers that may leak the
+// name and version of server software.
+var listVersionHeader = []string{
+	`Server`,
+	`X-AspNet-Version`,
+	`X-AspNetMvc-Version`,
+	`X-Powered-By`,
+}
+
+// HeadersSummary contains the site-wide summary of security headers
+// audit.
+type HeadersSummary struct {
+	// Rules contains the number of pages that has issue, by the name
+	// of rule.
+	Rules map[string]int `json:"rules,omitempty"`
+
+	// Pages the number of pages that has been audited.
+	Pages int `json:"pages"`
+}
+
+// add the issues on one page into summary.
+func (summary *HeadersSummary) add(listIssue []Issue) {
+	summary.Pages++
+	var seen = map[string]bool{}
+	for _, issue := range listIssue {
+		if !strings.HasPrefix(issue.Rule, `headers/`) || seen[issue.Rule] {
+			continue
+		}
+		seen[issue.Rule] = true
+		if summary.Rules == nil {
+			summary.Rules = map[string]int{}
+		}
+		summary.Rules[issue.Rule]++
+	}
+}
+
+// AuditHeaders fetch only the page in [Options.Url], without scanning
+// the links inside it, and audit its security headers.
+// The issues are stored in [Result.Issues] and the summary in
+// [Result.HeadersSummary].
+// To audit all pages in the website, use [Scan] with [Options.Headers].
+func AuditHeaders(ctx context.Context, opts Options) (result *Result, err error) {
+	var logp = `AuditHeaders`
+
+	var pageUrl *url.URL
+	pageUrl, err = url.Parse(opts.Url)
+	if err != nil || (pageUrl.Scheme != `http` && pageUrl.Scheme != `https`) {
+		return nil, fmt.Errorf(`%s: %q is not website URL`, logp,
+			opts.Url)
+	}
+	err = opts.init()
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	var httpReq *http.Request
+	httpReq, err = http.NewRequestWithContext(ctx, http.MethodGet,
+		opts.Url, nil)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	var (
+		httpc    = newHttpClient(opts.Insecure)
+		httpResp *http.Response
+	)
+	httpResp, err = httpc.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, ErrInterrupted)
+		}
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf(`%s: %s return status %d`, logp,
+			opts.Url, httpResp.StatusCode)
+	}
+
+	// The headers are audited from the last response after redirect.
+	var isHttps = httpResp.Request.URL.Scheme == `https`
+	var listIssue = auditHeaders(httpResp.Header, isHttps)
+
+	result = newResult()
+	result.HeadersSummary = &HeadersSummary{}
+	result.HeadersSummary.add(listIssue)
+	if len(listIssue) != 0 {
+		result.Issues = map[string][]Issue{
+			opts.Url: listIssue,
+		}
+	}
+	result.sort()
+	return result, nil
+}
+
+// auditHeaders return the issues on the security headers of page
+// response.
+// The header "Strict-Transport-Security" and the cookie attribute
+// "Secure" are only checked if the page is served using https.
+func auditHeaders(header http.Header, isHttps bool) (listIssue []Issue) {
+	var add = func(rule, msg string) {
+		listIssue = append(listIssue, Issue{
+			Rule:    rule,
+			Message: msg,
+		})
+	}
+
+	if isHttps {
+		var hsts = header.Get(`Strict-Transport-Security`)
+		if hsts == `` {
+			add(HeadersHstsMissing, `header Strict-Transport-Security`+
+				` is missing`)
+		} else {
+			var maxAge, ok = hstsMaxAge(hsts)
+			switch {
+			case !ok:
+				add(HeadersHstsMaxAge, `header`+
+					` Strict-Transport-Security does not have`+
+					` valid max-age`)
+			case maxAge < HstsMinMaxAge:
+				add(HeadersHstsMaxAge, fmt.Sprintf(`header`+
+					` Strict-Transport-Security max-age=%d is`+
+					` less than %d`, maxAge, HstsMinMaxAge))
+			}
+		}
+	}
+
+	var csp = header.Get(`Content-Security-Policy`)
+	if csp == `` {
+		add(HeadersCspMissing, `header Content-Security-Policy is missing`)
+	}
+
+	var val = header.Get(`X-Content-Type-Options`)
+	if !strings.EqualFold(strings.TrimSpace(val), `nosniff`) {
+		add(HeadersContentTypeOptions, `header X-Content-Type-Options`+
+			` is not "nosniff"`)
+	}
+
+	val = strings.ToLower(strings.TrimSpace(header.Get(`Referrer-Policy`)))
+	switch {
+	case val == ``:
+		add(HeadersReferrerPolicy, `header Referrer-Policy is missing`)
+	case strings.Contains(val, `unsafe-url`):
+		add(HeadersReferrerPolicy, `header Referrer-Policy is`+
+			` "unsafe-url"`)
+	}
+
+	if header.Get(`Permissions-Policy`) == `` {
+		add(HeadersPermissionsPolicy, `header Permissions-Policy is`+
+			` missing`)
+	}
+
+	val = strings.ToUpper(strings.TrimSpace(header.Get(`X-Frame-Options`)))
+	if val != `DENY` && val != `SAMEORIGIN` &&
+		!hasDirective(csp, `frame-ancestors`) {
+		add(HeadersFrameProtection, `header X-Frame-Options or`+
+			` Content-Security-Policy frame-ancestors is missing`)
+	}
+
+	var httpResp = &http.Response{Header: header}
+	for _, cookie := range httpResp.Cookies() {
+		if isHttps && !cookie.Secure {
+			add(HeadersCookieSecure, fmt.Sprintf(`cookie %q does not`+
+				` have attribute Secure`, cookie.Name))
+		}
+		if !cookie.HttpOnly {
+			add(HeadersCookieHttpOnly, fmt.Sprintf(`cookie %q does`+
+				` not have attribute HttpOnly`, cookie.Name))
+		}
+		if cookie.SameSite == 0 {
+			add(HeadersCookieSameSite, fmt.Sprintf(`cookie %q does`+
+				` not have attribute SameSite`, cookie.Name))
+		}
+	}
+
+	for _, name := range listVersionHeader {
+		val = header.Get(name)
+		if val == `` {
+			continue
+		}
+		if name == `Server` && !strings.ContainsAny(val, `0123456789`) {
+			// The name of server without version is fine.
+			continue
+		}
+		add(HeadersServerVersion, fmt.Sprintf(`header %s leak the`+
+			` server software %q`, name, val))
+	}
+	return listIssue
+}
+
+// hstsMaxAge return the value of directive "max-age" in header
+// "Strict-Transport-Security".
+func hstsMaxAge(hsts string) (maxAge int64, ok bool) {
+	for _, directive := range strings.Split(hsts, `;`) {
+		var name, val, _ = strings.Cut(strings.TrimSpace(directive), `=`)
+		if !strings.EqualFold(strings.TrimSpace(name), `max-age`) {
+			continue
+		}
+		val = strings.Trim(strings.TrimSpace(val), `"`)
+		var err error
+		maxAge, err = strconv.ParseInt(val, 10, 64)
+		return maxAge, err == nil
+	}
+	return 0, false
+}
+
+// hasDirective return true if the Content-Security-Policy contains the
+// directive name.
+func hasDirective(csp, name string) bool {
+	for _, directive := range strings.Split(csp, `;`) {
+		var fields = strings.Fields(directive)
+		if len(fields) != 0 && strings.EqualFold(fields[0], name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func newHeadersServer() (srv *httptest.Server) {
+	var mux = http.NewServeMux()
+	mux.HandleFunc(`/{$}`, func(w http.ResponseWriter, _ *http.Request) {
+		var header = w.Header()
+		header.Set(`Content-Type`, `text/html`)
+		header.Set(`Strict-Transport-Security`, `max-age=3600`)
+		header.Set(`Content-Security-Policy`,
+			`default-src 'self'; frame-ancestors 'none'`)
+		header.Set(`X-Content-Type-Options`, `nosniff`)
+		header.Set(`Referrer-Policy`, `unsafe-url`)
+		header.Set(`Server`, `nginx/1.18.0`)
+		header.Add(`Set-Cookie`, `sid=1; Path=/; HttpOnly`)
+		header.Add(`Set-Cookie`,
+			`theme=dark; Path=/; Secure; SameSite=Lax`)
+		w.Write([]byte(`<html><body><a href="/secure">Secure</a>` +
+			`</body></html>`))
+	})
+	mux.HandleFunc(`/secure`, func(w http.ResponseWriter, _ *http.Request) {
+		var header = w.Header()
+		header.Set(`Content-Type`, `text/html`)
+		header.Set(`Strict-Transport-Security`,
+			`max-age=31536000; includeSubDomains`)
+		header.Set(`Content-Security-Policy`, `default-src 'self'`)
+		header.Set(`X-Content-Type-Options`, `nosniff`)
+		header.Set(`X-Frame-Options`, `DENY`)
+		header.Set(`Referrer-Policy`, `strict-origin-when-cross-origin`)
+		header.Set(`Permissions-Policy`, `camera=()`)
+		header.Set(`Server`, `nginx`)
+		w.Write([]byte(`<html><body>Secure</body></html>`))
+	})
+	return httptest.NewTLSServer(mux)
+}
+
+func TestScan_headers(t *testing.T) {
+	var srv = newHeadersServer()
+	defer srv.Close()
+
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:      srv.URL,
+		Insecure: true,
+		NoCache:  true,
+		Headers:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expIssues = map[string][]brokenlinks.Issue{
+		srv.URL: {{
+			Rule:    brokenlinks.HeadersCookieHttpOnly,
+			Message: `cookie "theme" does not have attribute HttpOnly`,
+		}, {
+			Rule:    brokenlinks.HeadersCookieSameSite,
+			Message: `cookie "sid" does not have attribute SameSite`,
+		}, {
+			Rule:    brokenlinks.HeadersCookieSecure,
+			Message: `cookie "sid" does not have attribute Secure`,
+		}, {
+			Rule:    brokenlinks.HeadersHstsMaxAge,
+			Message: `header Strict-Transport-Security max-age=3600 is less than 15552000`,
+		}, {
+			Rule:    brokenlinks.HeadersPermissionsPolicy,
+			Message: `header Permissions-Policy is missing`,
+		}, {
+			Rule:    brokenlinks.HeadersReferrerPolicy,
+			Message: `header Referrer-Policy is "unsafe-url"`,
+		}, {
+			Rule:    brokenlinks.HeadersServerVersion,
+			Message: `header Server leak the server software "nginx/1.18.0"`,
+		}},
+	}
+	test.Assert(t, `Issues`, expIssues, got.Issues)
+
+	var expSummary = &brokenlinks.HeadersSummary{
+		Pages: 2,
+		Rules: map[string]int{
+			brokenlinks.HeadersCookieHttpOnly:    1,
+			brokenlinks.HeadersCookieSameSite:    1,
+			brokenlinks.HeadersCookieSecure:      1,
+			brokenlinks.HeadersHstsMaxAge:        1,
+			brokenlinks.HeadersPermissionsPolicy: 1,
+			brokenlinks.HeadersReferrerPolicy:    1,
+			brokenlinks.HeadersServerVersion:     1,
+		},
+	}
+	test.Assert(t, `HeadersSummary`, expSummary, got.HeadersSummary)
+}
+
+func TestScan_headersLocalDir(t *testing.T) {
+	var _, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     `testdata/local`,
+		Headers: true,
+	})
+	var expError = `Scan: Options: Headers cannot be used with local directory`
+	var gotError string
+	if err != nil {
+		gotError = err.Error()
+	}
+	test.Assert(t, `error`, expError, gotError)
+}
+
+func TestAuditHeaders(t *testing.T) {
+	var srv = newHeadersServer()
+	defer srv.Close()
+
+	var pageUrl = srv.URL + `/secure`
+	var got, err = brokenlinks.AuditHeaders(context.Background(),
+		brokenlinks.Options{
+			Url:      pageUrl,
+			Insecure: true,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `Issues`, map[string][]brokenlinks.Issue(nil),
+		got.Issues)
+	test.Assert(t, `HeadersSummary`,
+		&brokenlinks.HeadersSummary{Pages: 1}, got.HeadersSummary)
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// Inventory contains all links found during scan, sorted by URL.
+type Inventory []InventoryItem
+
+// InventoryItem the link found during scan.
+type InventoryItem struct {
+	// Url of the link, or the path of file when scanning local
+	// directory.
+	Url string `json:"url"`
+
+	// ContentType of the link, from HTTP response header Content-Type.
+	// Its empty if the link is not fetched, for example when its
+	// status loaded from cache.
+	ContentType string `json:"content_type"`
+
+	// Status the HTTP status code of the link, or [StatusBadLink].
+	Status int `json:"status"`
+
+	// Size of the link, derived from HTTP response Content-Length.
+	// Its -1 if the size is unknown.
+	Size int64 `json:"size"`
+
+	// ResponseTime the duration to fetch the link, in milliseconds.
+	ResponseTime int64 `json:"response_time"`
+
+	// Depth the minimum number of links from the [Options.Url] to
+	// this link.
+	// Its -1 if the link cannot be reached from the [Options.Url].
+	Depth int `json:"depth"`
+
+	// Inbound the number of pages that contains this link.
+	Inbound int `json:"inbound"`
+
+	// IsExternal true if the link is not under the [Options.Url].
+	IsExternal bool `json:"is_external"`
+}
+
+// newInventory create the inventory from the nodes in the graph.
+func newInventory(graph *Graph) (inv Inventory) {
+	var inbound = map[string]int{}
+	for _, edge := range graph.Edges {
+		inbound[edge.To]++
+	}
+	inv = make(Inventory, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		inv = append(inv, InventoryItem{
+			Url:          node.Url,
+			ContentType:  node.ContentType,
+			Status:       node.Status,
+			Size:         node.Size,
+			ResponseTime: node.ResponseTime,
+			Depth:        node.Depth,
+			Inbound:      inbound[node.Url],
+			IsExternal:   node.IsExternal,
+		})
+	}
+	return inv
+}
+
+// WriteJSON write the inventory in JSON format.
+func (inv Inventory) WriteJSON(w io.Writer) error {
+	var enc = json.NewEncoder(w)
+	enc.SetIndent(``, `  `)
+	return enc.Encode(inv)
+}
+
+// WriteCSV write the inventory in CSV format, with the header in the
+// first line.
+// The column is the JSON name of field in [InventoryItem].
+func (inv Inventory) WriteCSV(w io.Writer) error {
+	var cw = csv.NewWriter(w)
+	var err = cw.Write([]string{
+		`url`,
+		`status`,
+		`content_type`,
+		`size`,
+		`response_time`,
+		`depth`,
+		`inbound`,
+		`is_external`,
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range inv {
+		err = cw.Write([]string{
+			item.Url,
+			strconv.Itoa(item.Status),
+			item.ContentType,
+			strconv.FormatInt(item.Size, 10),
+			strconv.FormatInt(item.ResponseTime, 10),
+			strconv.Itoa(item.Depth),
+			strconv.Itoa(item.Inbound),
+			strconv.FormatBool(item.IsExternal),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
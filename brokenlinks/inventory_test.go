@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_inventory(t *testing.T) {
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:       filepath.Join(`testdata`, `local`),
+		NoCache:   true,
+		Inventory: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Graph != nil {
+		t.Fatalf(`expecting nil Graph, got %d nodes`,
+			len(got.Graph.Nodes))
+	}
+	for x := range got.Inventory {
+		// The response time is different on each scan.
+		got.Inventory[x].ResponseTime = 0
+	}
+
+	var buf bytes.Buffer
+	err = got.Inventory.WriteCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = `url,status,content_type,size,response_time,depth,inbound,is_external
+testdata/local/about.html,200,text/html; charset=utf-8,243,0,1,2,false
+testdata/local/docs/guide.html,404,text/plain; charset=utf-8,19,0,2,1,false
+testdata/local/docs/index.html,200,text/html; charset=utf-8,209,0,1,2,false
+testdata/local/docs/missing.png,404,text/plain; charset=utf-8,19,0,2,1,false
+testdata/local/index.html,200,text/html; charset=utf-8,284,0,0,1,false
+testdata/local/missing,404,text/plain; charset=utf-8,19,0,1,1,false
+testdata/local/missing.png,404,text/plain; charset=utf-8,19,0,1,1,false
+`
+	test.Assert(t, `WriteCSV`, exp, buf.String())
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"slices"
+	"strings"
+)
+
+// Issue store the problem found on the page by audit.
+type Issue struct {
+	// Rule the name of rule that report the issue, prefixed with the
+	// name of audit, for example "seo/title-missing".
+	Rule string `json:"rule"`
+
+	// Message the description of the issue.
+	Message string `json:"message"`
+
+	// Link the other page or link related to the issue, for example
+	// the page with duplicate title.
+	Link string `json:"link,omitempty"`
+
+	// Line and Column of the element with the issue inside the HTML
+	// source of the page, start from 1.
+	// Its zero if the issue is not on specific element.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// sortIssues sort the issues on each page by its position, rule, and
+// link.
+func sortIssues(issues map[string][]Issue) {
+	for _, listIssue := range issues {
+		slices.SortStableFunc(listIssue, func(a, b Issue) int {
+			if a.Line != b.Line {
+				return a.Line - b.Line
+			}
+			if a.Column != b.Column {
+				return a.Column - b.Column
+			}
+			var cmp = strings.Compare(a.Rule, b.Rule)
+			if cmp != 0 {
+				return cmp
+			}
+			return strings.Compare(a.Link, b.Link)
+		})
+	}
+}
@@ -5,6 +5,8 @@ package brokenlinks
 
 import (
 	"net/url"
+	"strings"
+	"time"
 
 	"golang.org/x/net/html/atom"
 )
@@ -18,6 +20,9 @@ type linkQueue struct {
 	// url being scanned.
 	url string
 
+	// text of the anchor or alternate text of image.
+	text string
+
 	// kind of url, its either an anchor or image.
 	// It set to 0 if url is the first URL being scanned.
 	kind atom.Atom
@@ -35,4 +40,50 @@ type linkQueue struct {
 
 	// Size of the page, derived from HTTP response ContentLength.
 	size int64
+
+	// contentType of the page, from HTTP response header Content-Type.
+	contentType string
+
+	// responseTime the duration to fetch the link.
+	responseTime time.Duration
+
+	// redirectUrl the final URL of link after following redirect, and
+	// redirectStatus the HTTP status code of the last redirect
+	// response.
+	// Both are empty if the link is not redirected.
+	redirectUrl    string
+	redirectStatus int
+
+	// meta the information about the internal HTML page.
+	meta pageMeta
+
+	// issues found on the internal HTML page by audit.
+	issues []Issue
+
+	// line and column of the link inside the parent page, start from 1.
+	line   int
+	column int
+
+	// count number of the same link found inside the parent page.
+	count int
+}
+
+// isRedirected return true if the link redirected to other page.
+// The redirect that only add or remove the trailing slash, for example
+// from "/docs" to "/docs/", is not counted.
+func (linkq *linkQueue) isRedirected() bool {
+	if linkq.redirectUrl == `` {
+		return false
+	}
+	return strings.TrimSuffix(linkq.redirectUrl, `/`) !=
+		strings.TrimSuffix(linkq.url, `/`)
+}
+
+// parent return the parent URL as string, or empty string if the link
+// does not have parent.
+func (linkq *linkQueue) parent() string {
+	if linkq.parentUrl == nil {
+		return ``
+	}
+	return linkq.parentUrl.String()
 }
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+// linkStore store the status of links that has been seen, the queue of
+// links that waiting to be scanned, and the links that waiting for the
+// status of the same link being scanned.
+// It only accessed by the goroutine that process the result of scan, so
+// the implementation does not need to be safe for concurrent use.
+type linkStore interface {
+	// seen return the status of link and true if the link has been
+	// seen.
+	seen(url string) (status int, ok bool, err error)
+
+	// setSeen set the status of link.
+	setSeen(url string, status int) error
+
+	// walkSeen call fn for each link that has been seen, until fn
+	// return an error.
+	walkSeen(fn func(url string, status int) error) error
+
+	// push the link to the end of queue.
+	push(linkq linkQueue) error
+
+	// pop remove and return the link in the front of queue.
+	// It return false if the queue is empty.
+	pop() (linkq linkQueue, ok bool, err error)
+
+	// len return the number of links in the queue.
+	len() int
+
+	// wait add the link, found inside the page, that waiting for the
+	// status of the same link being scanned.
+	wait(linkq linkQueue) error
+
+	// done remove and return the links that waiting for the status of
+	// url.
+	done(url string) (listWait []linkQueue, err error)
+
+	// nwait return the number of links that waiting for status.
+	nwait() int
+
+	// close release the resources used by store.
+	close() error
+}
+
+// memoryLinkStore implement linkStore in memory.
+type memoryLinkStore struct {
+	seenLink map[string]int
+
+	// waiting contains the links that waiting for status, by the
+	// URL of link.
+	waiting map[string][]linkQueue
+
+	queue []linkQueue
+
+	// nwaiting the number of links in waiting.
+	nwaiting int
+}
+
+func newMemoryLinkStore() *memoryLinkStore {
+	return &memoryLinkStore{
+		seenLink: map[string]int{},
+		waiting:  map[string][]linkQueue{},
+	}
+}
+
+func (store *memoryLinkStore) seen(url string) (status int, ok bool, err error) {
+	status, ok = store.seenLink[url]
+	return status, ok, nil
+}
+
+func (store *memoryLinkStore) setSeen(url string, status int) error {
+	store.seenLink[url] = status
+	return nil
+}
+
+func (store *memoryLinkStore) walkSeen(fn func(url string, status int) error) (err error) {
+	for url, status := range store.seenLink {
+		err = fn(url, status)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *memoryLinkStore) push(linkq linkQueue) error {
+	store.queue = append(store.queue, linkq)
+	return nil
+}
+
+func (store *memoryLinkStore) pop() (linkq linkQueue, ok bool, err error) {
+	if len(store.queue) == 0 {
+		return linkq, false, nil
+	}
+	linkq = store.queue[0]
+	store.queue[0] = linkQueue{}
+	store.queue = store.queue[1:]
+	return linkq, true, nil
+}
+
+func (store *memoryLinkStore) len() int {
+	return len(store.queue)
+}
+
+func (store *memoryLinkStore) wait(linkq linkQueue) error {
+	store.waiting[linkq.url] = append(store.waiting[linkq.url], linkq)
+	store.nwaiting++
+	return nil
+}
+
+func (store *memoryLinkStore) done(url string) (listWait []linkQueue, err error) {
+	listWait = store.waiting[url]
+	delete(store.waiting, url)
+	store.nwaiting -= len(listWait)
+	return listWait, nil
+}
+
+func (store *memoryLinkStore) nwait() int {
+	return store.nwaiting
+}
+
+func (store *memoryLinkStore) close() error {
+	return nil
+}
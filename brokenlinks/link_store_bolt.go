@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// linkStoreFileName the name of database file inside the
+// [Options.DiskDir].
+const linkStoreFileName = `links.db`
+
+var (
+	boltBucketSeen    = []byte(`seen`)
+	boltBucketQueue   = []byte(`queue`)
+	boltBucketWaiting = []byte(`waiting`)
+)
+
+// boltLinkStore implement linkStore using bbolt database, so the seen
+// links, the queue, and the waiting links are not hold in memory.
+// The database is temporary, it is created on open and removed on close.
+type boltLinkStore struct {
+	db *bolt.DB
+
+	// nqueue the number of links in the queue.
+	nqueue int
+
+	// nwaiting the number of links in the bucket waiting.
+	nwaiting int
+}
+
+// openBoltLinkStore create new database inside the directory dir.
+// The existing database, from the previous scan, is removed.
+func openBoltLinkStore(dir string) (store *boltLinkStore, err error) {
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	var file = filepath.Join(dir, linkStoreFileName)
+	err = os.Remove(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// The database is removed after scan, so there is no need to
+	// sync each write to disk.
+	// The list of free pages is kept in memory, where the type "map"
+	// use less memory than "array" on large database.
+	var opts = &bolt.Options{
+		NoSync:         true,
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	}
+
+	store = &boltLinkStore{}
+	store.db, err = bolt.Open(file, 0600, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.db.Update(func(tx *bolt.Tx) (errCreate error) {
+		_, errCreate = tx.CreateBucket(boltBucketSeen)
+		if errCreate != nil {
+			return errCreate
+		}
+		_, errCreate = tx.CreateBucket(boltBucketQueue)
+		if errCreate != nil {
+			return errCreate
+		}
+		_, errCreate = tx.CreateBucket(boltBucketWaiting)
+		return errCreate
+	})
+	if err != nil {
+		_ = store.close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *boltLinkStore) seen(url string) (status int, ok bool, err error) {
+	err = store.db.View(func(tx *bolt.Tx) (errView error) {
+		var v = tx.Bucket(boltBucketSeen).Get([]byte(url))
+		if v == nil {
+			return nil
+		}
+		ok = true
+		status, errView = strconv.Atoi(string(v))
+		return errView
+	})
+	return status, ok, err
+}
+
+func (store *boltLinkStore) setSeen(url string, status int) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		var v = []byte(strconv.Itoa(status))
+		return tx.Bucket(boltBucketSeen).Put([]byte(url), v)
+	})
+}
+
+func (store *boltLinkStore) walkSeen(fn func(url string, status int) error) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucketSeen).ForEach(func(k, v []byte) error {
+			var status, err = strconv.Atoi(string(v))
+			if err != nil {
+				return err
+			}
+			return fn(string(k), status)
+		})
+	})
+}
+
+func (store *boltLinkStore) push(linkq linkQueue) (err error) {
+	var v []byte
+	v, err = json.Marshal(newStateLink(linkq))
+	if err != nil {
+		return err
+	}
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		var bucket = tx.Bucket(boltBucketQueue)
+		var seq, errSeq = bucket.NextSequence()
+		if errSeq != nil {
+			return errSeq
+		}
+		var k = binary.BigEndian.AppendUint64(nil, seq)
+		return bucket.Put(k, v)
+	})
+	if err != nil {
+		return err
+	}
+	store.nqueue++
+	return nil
+}
+
+func (store *boltLinkStore) pop() (linkq linkQueue, ok bool, err error) {
+	var slink stateLink
+	err = store.db.Update(func(tx *bolt.Tx) (errUpdate error) {
+		var cursor = tx.Bucket(boltBucketQueue).Cursor()
+		var k, v = cursor.First()
+		if k == nil {
+			return nil
+		}
+		errUpdate = json.Unmarshal(v, &slink)
+		if errUpdate != nil {
+			return errUpdate
+		}
+		ok = true
+		return cursor.Delete()
+	})
+	if err != nil || !ok {
+		return linkq, false, err
+	}
+	store.nqueue--
+
+	linkq, err = slink.linkQueue()
+	if err != nil {
+		return linkq, false, err
+	}
+	return linkq, true, nil
+}
+
+func (store *boltLinkStore) len() int {
+	return store.nqueue
+}
+
+// wait store the link using key with the URL of link, followed by zero
+// byte and the sequence number, so the links that waiting for the same
+// URL are stored next to each other.
+func (store *boltLinkStore) wait(linkq linkQueue) (err error) {
+	var v []byte
+	v, err = json.Marshal(newStateLink(linkq))
+	if err != nil {
+		return err
+	}
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		var bucket = tx.Bucket(boltBucketWaiting)
+		var seq, errSeq = bucket.NextSequence()
+		if errSeq != nil {
+			return errSeq
+		}
+		var k = append([]byte(linkq.url), 0)
+		k = binary.BigEndian.AppendUint64(k, seq)
+		return bucket.Put(k, v)
+	})
+	if err != nil {
+		return err
+	}
+	store.nwaiting++
+	return nil
+}
+
+func (store *boltLinkStore) done(url string) (listWait []linkQueue, err error) {
+	var prefix = url + "\x00"
+	err = store.db.Update(func(tx *bolt.Tx) (errUpdate error) {
+		var (
+			bucket   = tx.Bucket(boltBucketWaiting)
+			cursor   = bucket.Cursor()
+			listKey  [][]byte
+			listLink []stateLink
+		)
+		var k, v = cursor.Seek([]byte(prefix))
+		for ; k != nil && strings.HasPrefix(string(k), prefix); k, v = cursor.Next() {
+			var slink stateLink
+			errUpdate = json.Unmarshal(v, &slink)
+			if errUpdate != nil {
+				return errUpdate
+			}
+			listKey = append(listKey, k)
+			listLink = append(listLink, slink)
+		}
+		for _, k = range listKey {
+			errUpdate = bucket.Delete(k)
+			if errUpdate != nil {
+				return errUpdate
+			}
+		}
+		for _, slink := range listLink {
+			var linkq linkQueue
+			linkq, errUpdate = slink.linkQueue()
+			if errUpdate != nil {
+				return errUpdate
+			}
+			listWait = append(listWait, linkq)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	store.nwaiting -= len(listWait)
+	return listWait, nil
+}
+
+func (store *boltLinkStore) nwait() int {
+	return store.nwaiting
+}
+
+// close the database and remove its file.
+func (store *boltLinkStore) close() (err error) {
+	var file = store.db.Path()
+	err = store.db.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(file)
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localServer serve the files in local directory using HTTP server in the
+// same process, so the directory can be scanned like website.
+type localServer struct {
+	srv *http.Server
+
+	// dir the directory being served.
+	dir string
+
+	// url the address of server, for example "http://127.0.0.1:34567".
+	url string
+}
+
+// newLocalServer serve the directory dir on random port in the loopback
+// address.
+func newLocalServer(dir string) (local *localServer, err error) {
+	var listener net.Listener
+	listener, err = net.Listen(`tcp`, `127.0.0.1:0`)
+	if err != nil {
+		return nil, err
+	}
+
+	local = &localServer{
+		dir: dir,
+		url: `http://` + listener.Addr().String(),
+	}
+	local.srv = &http.Server{
+		Handler:        local,
+		MaxHeaderBytes: 1 << 20,
+	}
+	go func() {
+		// The Serve always return non-nil error, either
+		// [http.ErrServerClosed] after close or when the listener
+		// closed, which in both cases the scan has been completed.
+		_ = local.srv.Serve(listener)
+	}()
+	return local, nil
+}
+
+// ServeHTTP serve the file in directory resolved by [localServer.resolve].
+func (local *localServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	var file, ok = local.resolve(req.URL.Path)
+	if !ok {
+		http.NotFound(resp, req)
+		return
+	}
+
+	var f, err = os.Open(file)
+	if err != nil {
+		http.NotFound(resp, req)
+		return
+	}
+	defer f.Close()
+
+	var fi os.FileInfo
+	fi, err = f.Stat()
+	if err != nil {
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(resp, req, fi.Name(), fi.ModTime(), f)
+}
+
+// resolve return the path to the file in directory for the URL path,
+// using the following rules, in order,
+//
+//   - the file with the same path,
+//   - the file "index.html" inside the directory with the same path, or
+//   - the file with the same path plus ".html", for example "/about"
+//     resolved to "about.html".
+//
+// If none of the file exist, it return the path joined with directory and
+// false.
+func (local *localServer) resolve(urlPath string) (file string, ok bool) {
+	urlPath = path.Clean(`/` + urlPath)
+	file = filepath.Join(local.dir, filepath.FromSlash(urlPath))
+
+	var listFile = []string{
+		file,
+		filepath.Join(file, `index.html`),
+		file + `.html`,
+	}
+	for _, name := range listFile {
+		var fi, err = os.Stat(name)
+		if err == nil && !fi.IsDir() {
+			return name, true
+		}
+	}
+	return file, false
+}
+
+// path return the path to the file in directory for the link served by
+// local server, or the link itself if its not served by local server.
+func (local *localServer) path(link string) string {
+	var rest, ok = strings.CutPrefix(link, local.url)
+	if !ok {
+		return link
+	}
+	var linkUrl, err = url.Parse(rest)
+	if err != nil {
+		return link
+	}
+	var file, _ = local.resolve(linkUrl.Path)
+	return file
+}
+
+// link return the URL of file in directory served by local server.
+// This is the reverse of [localServer.path].
+func (local *localServer) link(file string) string {
+	var rel, err = filepath.Rel(local.dir, file)
+	if err != nil || isOutsideDir(rel) {
+		return file
+	}
+	rel = trimIndexHtml(filepath.ToSlash(rel))
+	rel = strings.TrimSuffix(rel, `/`)
+	if rel == `.` || rel == `` {
+		return local.url
+	}
+	return local.url + `/` + rel
+}
+
+// canonical return the link to the file in directory, so different links
+// to the same file, for example "/about" and "/about.html", are scanned
+// only once.
+func (local *localServer) canonical(link string) string {
+	if !strings.HasPrefix(link, local.url) {
+		return link
+	}
+	return local.link(local.path(link))
+}
+
+// urlPath return the absolute path of link served by local server, where
+// the file "index.html" is replaced with its directory, for example
+// "/docs/".
+func (local *localServer) urlPath(link string) string {
+	var rel, err = filepath.Rel(local.dir, local.path(link))
+	if err != nil || isOutsideDir(rel) {
+		return link
+	}
+	return `/` + trimIndexHtml(filepath.ToSlash(rel))
+}
+
+// isOutsideDir return true if the relative path rel, as returned by
+// [filepath.Rel], is outside of the directory.
+func isOutsideDir(rel string) bool {
+	return rel == `..` ||
+		strings.HasPrefix(rel, `..`+string(filepath.Separator))
+}
+
+// trimIndexHtml remove the file name "index.html" from the slash
+// separated path rel, for example "docs/index.html" become "docs/", while
+// the other file like "docs/myindex.html" is not changed.
+func trimIndexHtml(rel string) string {
+	if rel == `index.html` {
+		return ``
+	}
+	var dir, ok = strings.CutSuffix(rel, `/index.html`)
+	if ok {
+		return dir + `/`
+	}
+	return rel
+}
+
+// close shutdown the server.
+func (local *localServer) close() error {
+	return local.srv.Shutdown(context.Background())
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_localDir(t *testing.T) {
+	var absDir, err = filepath.Abs(`testdata/local`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		dir string
+		url string
+	}
+	var listCase = []testCase{{
+		dir: filepath.Join(`testdata`, `local`),
+		url: `testdata/local`,
+	}, {
+		dir: absDir,
+		url: `file://` + filepath.ToSlash(absDir),
+	}}
+
+	for _, tcase := range listCase {
+		var opts = brokenlinks.Options{
+			Url:     tcase.url,
+			NoCache: true,
+		}
+		var got *brokenlinks.Result
+		got, err = brokenlinks.Scan(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var dir = tcase.dir
+		var exp = map[string][]brokenlinks.Broken{
+			filepath.Join(dir, `index.html`): {{
+				Link:    filepath.Join(dir, `missing`),
+				Text:    `Missing`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    9,
+				Column:  5,
+				Count:   1,
+			}, {
+				Link:    filepath.Join(dir, `missing.png`),
+				Text:    `Missing image`,
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    10,
+				Column:  5,
+				Count:   1,
+			}},
+			filepath.Join(dir, `about.html`): {{
+				Link:    filepath.Join(dir, `docs`, `guide.html`),
+				Text:    `Guide`,
+				Element: `a@href`,
+				Code:    http.StatusNotFound,
+				Line:    8,
+				Column:  5,
+				Count:   1,
+			}},
+			filepath.Join(dir, `docs`, `index.html`): {{
+				Link:    filepath.Join(dir, `docs`, `missing.png`),
+				Element: `img@src`,
+				Code:    http.StatusNotFound,
+				Line:    8,
+				Column:  5,
+				Count:   1,
+			}},
+		}
+		test.Assert(t, tcase.url, exp, got.BrokenLinks)
+	}
+}
+
+func TestScan_localDirInvalid(t *testing.T) {
+	type testCase struct {
+		url      string
+		expError string
+	}
+	var listCase = []testCase{{
+		url:      `testdata/local/index.html`,
+		expError: `Scan: Options: "testdata/local/index.html" is not a directory`,
+	}, {
+		url:      `testdata/notexist`,
+		expError: `Scan: Options: stat testdata/notexist: no such file or directory`,
+	}}
+	for _, tcase := range listCase {
+		var _, err = brokenlinks.Scan(brokenlinks.Options{
+			Url: tcase.url,
+		})
+		var gotError string
+		if err != nil {
+			gotError = err.Error()
+		}
+		test.Assert(t, tcase.url, tcase.expError, gotError)
+	}
+}
+
+// TestScan_localDirIndexName test the file which name end with
+// "index.html" or start with "..", that are not the index of directory nor
+// outside of directory.
+func TestScan_localDirIndexName(t *testing.T) {
+	var dir = t.TempDir()
+	var listFile = map[string]string{
+		`index.html`: `<html><body>` +
+			`<a href="/docs/myindex.html">My index</a>` +
+			`<a href="/..notes.html">Notes</a>` +
+			`</body></html>`,
+		`docs/myindex.html`: `<html><body><a href="/">Home</a></body></html>`,
+		`..notes.html`:      `<html><body><a href="/">Home</a></body></html>`,
+	}
+	var err = os.Mkdir(filepath.Join(dir, `docs`), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range listFile {
+		err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got *brokenlinks.Result
+	got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     dir,
+		NoCache: true,
+		Sitemap: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `BrokenLinks`, map[string][]brokenlinks.Broken{},
+		got.BrokenLinks)
+
+	var expSitemap = brokenlinks.Sitemap{
+		{Loc: `/`},
+		{Loc: `/..notes.html`},
+		{Loc: `/docs/myindex.html`},
+	}
+	for x := range got.Sitemap {
+		got.Sitemap[x].LastMod = ``
+	}
+	test.Assert(t, `Sitemap`, expSitemap, got.Sitemap)
+}
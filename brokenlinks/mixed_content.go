@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// List of rules reported by mixed content check.
+const (
+	// MixedContentActive the insecure resource that can modify the
+	// page, for example script, stylesheet, and iframe.
+	// The browser block the active mixed content.
+	MixedContentActive = `mixed-content/active`
+
+	// MixedContentPassive the insecure resource that only displayed in
+	// the page, for example image, audio, and video.
+	// The browser load the passive mixed content with warning, or
+	// upgrade it to https.
+	MixedContentPassive = `mixed-content/passive`
+)
+
+// subresource the link to the insecure resource inside the HTML page.
+type subresource struct {
+	// value the URL of resource, with scheme "http".
+	value string
+
+	// element and attribute where the link found, for example
+	// "script@src".
+	element string
+
+	line   int
+	column int
+
+	isActive bool
+}
+
+// extractInsecure parse the HTML content and return the list of
+// subresources that loaded using scheme "http", ordered by their position
+// in the content.
+func extractInsecure(content []byte) (listRes []subresource) {
+	var (
+		tokenizer = html.NewTokenizer(bytes.NewReader(content))
+		line      = 1
+		column    = 1
+	)
+
+	var add = func(token html.Token, attr string, isActive bool,
+		line, column int,
+	) {
+		var val, _ = attrValue(token.Attr, attr)
+		var listVal = []string{val}
+		if attr == `srcset` {
+			listVal = parseSrcset(val)
+		}
+		for _, val = range listVal {
+			val = strings.TrimSpace(val)
+			if len(val) < 7 || !strings.EqualFold(val[:7], `http://`) {
+				continue
+			}
+			listRes = append(listRes, subresource{
+				value:    val,
+				element:  token.Data + `@` + attr,
+				line:     line,
+				column:   column,
+				isActive: isActive,
+			})
+		}
+	}
+
+	for {
+		var (
+			tokenType = tokenizer.Next()
+			startLine = line
+			startCol  = column
+		)
+		line, column = advancePosition(tokenizer.Raw(), line, column)
+
+		switch tokenType {
+		case html.ErrorToken:
+			return listRes
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			var token = tokenizer.Token()
+			switch token.DataAtom {
+			case atom.Script, atom.Iframe, atom.Embed:
+				add(token, `src`, true, startLine, startCol)
+			case atom.Object:
+				add(token, `data`, true, startLine, startCol)
+			case atom.Img:
+				add(token, `src`, false, startLine, startCol)
+				add(token, `srcset`, false, startLine, startCol)
+			case atom.Audio, atom.Video, atom.Track:
+				add(token, `src`, false, startLine, startCol)
+			case atom.Source:
+				add(token, `src`, false, startLine, startCol)
+				add(token, `srcset`, false, startLine, startCol)
+			case atom.Link:
+				var rel, _ = attrValue(token.Attr, `rel`)
+				switch {
+				case hasToken(rel, `stylesheet`),
+					hasToken(rel, `preload`),
+					hasToken(rel, `modulepreload`),
+					hasToken(rel, `manifest`):
+					add(token, `href`, true, startLine, startCol)
+				case hasToken(rel, `icon`),
+					hasToken(rel, `apple-touch-icon`):
+					add(token, `href`, false, startLine, startCol)
+				}
+			}
+		}
+	}
+}
+
+// parseSrcset return the list of URL in the value of attribute
+// "srcset", for example "a.png 1x, b.png 2x".
+func parseSrcset(srcset string) (listUrl []string) {
+	for _, candidate := range strings.Split(srcset, `,`) {
+		var fields = strings.Fields(candidate)
+		if len(fields) != 0 {
+			listUrl = append(listUrl, fields[0])
+		}
+	}
+	return listUrl
+}
+
+// httpsChecker check if the insecure resource is available over https.
+// The result of check is shared between pages, so each resource is
+// requested only once.
+type httpsChecker struct {
+	httpc *http.Client
+
+	// available contains the result of check by the URL of resource.
+	available map[string]bool
+
+	// mtx protect the available.
+	mtx sync.Mutex
+}
+
+func newHttpsChecker(httpc *http.Client) *httpsChecker {
+	return &httpsChecker{
+		httpc:     httpc,
+		available: map[string]bool{},
+	}
+}
+
+// isAvailable return true if the resource, with its scheme changed from
+// "http" to "https", return status 2xx or 3xx on HTTP method HEAD, or GET
+// if the server does not allow HEAD.
+// The result is stored only if the server response the request, so the
+// resource that cannot be requested, for example due to timeout, is
+// checked again on the next page.
+func (checker *httpsChecker) isAvailable(ctx context.Context, res string) bool {
+	checker.mtx.Lock()
+	var isAvailable, ok = checker.available[res]
+	checker.mtx.Unlock()
+	if ok {
+		return isAvailable
+	}
+
+	var httpsUrl = `https://` + res[len(`http://`):]
+	var status, err = checker.status(ctx, http.MethodHead, httpsUrl)
+	if err == nil && status == http.StatusMethodNotAllowed {
+		status, err = checker.status(ctx, http.MethodGet, httpsUrl)
+	}
+	if err != nil {
+		return false
+	}
+	isAvailable = status < http.StatusBadRequest
+
+	checker.mtx.Lock()
+	checker.available[res] = isAvailable
+	checker.mtx.Unlock()
+	return isAvailable
+}
+
+// status request the link using the HTTP method and return the response
+// status code.
+func (checker *httpsChecker) status(
+	ctx context.Context, method, link string,
+) (status int, err error) {
+	var httpReq *http.Request
+	httpReq, err = http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, err
+	}
+	var httpResp *http.Response
+	httpResp, err = checker.httpc.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	httpResp.Body.Close()
+	return httpResp.StatusCode, nil
+}
+
+// audit return the issues for each insecure subresource in the HTML
+// content, with note whether the resource is available over https.
+func (checker *httpsChecker) audit(ctx context.Context, content []byte) (
+	listIssue []Issue,
+) {
+	for _, res := range extractInsecure(content) {
+		var (
+			rule = MixedContentPassive
+			kind = `passive`
+			note = `not available over https`
+		)
+		if res.isActive {
+			rule = MixedContentActive
+			kind = `active`
+		}
+		if checker.isAvailable(ctx, res.value) {
+			note = `available over https`
+		}
+		listIssue = append(listIssue, Issue{
+			Rule: rule,
+			Message: fmt.Sprintf(`%s mixed content on %s, %s`,
+				kind, res.element, note),
+			Link:   res.value,
+			Line:   res.line,
+			Column: res.column,
+		})
+	}
+	return listIssue
+}
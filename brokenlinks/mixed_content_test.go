@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_mixedContent(t *testing.T) {
+	// The insecure server that does not serve https.
+	var httpSrv = httptest.NewServer(http.NotFoundHandler())
+	defer httpSrv.Close()
+
+	var srv = httptest.NewTLSServer(nil)
+	defer srv.Close()
+
+	var (
+		httpHost = strings.TrimPrefix(httpSrv.URL, `http://`)
+		tlsHost  = strings.TrimPrefix(srv.URL, `https://`)
+	)
+
+	var page = `<html>
+<head>
+<link rel="canonical" href="http://{{.TLS}}/">
+<link rel="stylesheet" href="http://{{.TLS}}/style.css">
+<script src="http://{{.HTTP}}/app.js"></script>
+<script src="/local.js"></script>
+</head>
+<body>
+<video src="http://{{.HTTP}}/movie.mp4"></video>
+<iframe src="http://{{.TLS}}/frame.html"></iframe>
+<img src="http://{{.TLS}}/get-only.png">
+</body>
+</html>`
+	page = strings.ReplaceAll(page, `{{.TLS}}`, tlsHost)
+	page = strings.ReplaceAll(page, `{{.HTTP}}`, httpHost)
+
+	var mux = http.NewServeMux()
+	mux.HandleFunc(`/{$}`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(`Content-Type`, `text/html`)
+		w.Write([]byte(page))
+	})
+	mux.HandleFunc(`/local.js`, func(_ http.ResponseWriter, _ *http.Request) {})
+	mux.HandleFunc(`/style.css`, func(_ http.ResponseWriter, _ *http.Request) {})
+	mux.HandleFunc(`/frame.html`, func(_ http.ResponseWriter, _ *http.Request) {})
+	mux.HandleFunc(`/get-only.png`, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	srv.Config.Handler = mux
+
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:          srv.URL,
+		Insecure:     true,
+		NoCache:      true,
+		MixedContent: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Issue{
+		srv.URL: {{
+			Rule:    brokenlinks.MixedContentActive,
+			Message: `active mixed content on link@href, available over https`,
+			Link:    `http://` + tlsHost + `/style.css`,
+			Line:    4,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.MixedContentActive,
+			Message: `active mixed content on script@src, not available over https`,
+			Link:    httpSrv.URL + `/app.js`,
+			Line:    5,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.MixedContentPassive,
+			Message: `passive mixed content on video@src, not available over https`,
+			Link:    httpSrv.URL + `/movie.mp4`,
+			Line:    9,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.MixedContentActive,
+			Message: `active mixed content on iframe@src, available over https`,
+			Link:    `http://` + tlsHost + `/frame.html`,
+			Line:    10,
+			Column:  1,
+		}, {
+			Rule:    brokenlinks.MixedContentPassive,
+			Message: `passive mixed content on img@src, available over https`,
+			Link:    `http://` + tlsHost + `/get-only.png`,
+			Line:    11,
+			Column:  1,
+		}},
+	}
+	test.Assert(t, `Issues`, exp, got.Issues)
+}
@@ -5,20 +5,93 @@ package brokenlinks
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"git.sr.ht/~shulhan/jarink"
+)
+
+// List of default value for options.
+const (
+	// DefaultCacheTTL default value for [Options.CacheTTL].
+	DefaultCacheTTL = 7 * 24 * time.Hour
+
+	// DefaultCacheFailTTL default value for [Options.CacheFailTTL].
+	DefaultCacheFailTTL = time.Hour
+
+	// DefaultMaxConcurrent default value for [Options.MaxConcurrent].
+	DefaultMaxConcurrent = 100
 )
 
 // Options define the options for scanning broken links.
 type Options struct {
 	// The URL to be scanned.
+	// The Url can be path to the local directory or URL with scheme
+	// "file", for example "./public" or "file:///path/to/public".
+	// The local directory is served by HTTP server in the same process
+	// and the broken links are reported using the path of files.
 	Url     string
 	scanUrl *url.URL
 
+	// localDir the directory to be scanned, if the Url is local
+	// directory.
+	localDir string
+
+	// Logger used to print the information while scanning.
+	// If its nil, the default logger will print to stderr in text
+	// format with level set to [slog.LevelWarn], or [slog.LevelDebug] if
+	// IsVerbose is true.
+	Logger *slog.Logger
+
+	// OnBroken the function that called for each broken link found,
+	// instead of storing it in the [Result.BrokenLinks], so the memory
+	// usage does not grow with the number of broken links.
+	// The broken link that match with baseline is not passed to
+	// OnBroken but stored in [Result.Suppressed].
+	// The function is called from single goroutine.
+	OnBroken func(page string, broken Broken)
+
+	// Cache the storage for scanned external links.
+	// If its nil, the cache will be opened from CacheFile.
+	// The Cache is saved after scan, but not closed.
+	Cache jarink.CacheStore
+
 	PastResultFile string
 
+	// BaselineFile path to JSON file that contains list of accepted
+	// broken links.
+	// See [Baseline] for its format.
+	BaselineFile string
+	baseline     *Baseline
+
+	// CacheFile path to the cache file, opened using [jarink.OpenCache].
+	// If its empty, the default cache file is used.
+	CacheFile string
+
+	// StateDir path to the directory to store the state of scan.
+	// The state contains the links that has not been scanned, the links
+	// that has been scanned, and the partial result.
+	// The state is saved every minute while scanning and when the scan
+	// is interrupted, and removed once the scan is completed.
+	StateDir string
+
+	// DiskDir path to the directory to store the links that has been
+	// scanned, the links that waiting to be scanned, and the links that
+	// waiting for the status of the same link being scanned, instead of
+	// storing them in memory.
+	// The database in the directory is removed once the scan is
+	// completed.
+	// The broken links does not have [Broken.Suggestions], since it
+	// require loading all of scanned links into memory.
+	// This option cannot be used with StateDir.
+	DiskDir string
+
 	// IgnoreStatus comma separated list HTTP status code that will be
 	// ignored on scan.
 	// Page that return one of the IgnoreStatus will be assumed as
@@ -27,10 +100,105 @@ type Options struct {
 	IgnoreStatus string
 	ignoreStatus []int
 
+	// MaxConcurrent the maximum number of links scanned at the same
+	// time.
+	// Default to [DefaultMaxConcurrent].
+	MaxConcurrent int
+
+	// CacheTTL the duration where the external link that successfully
+	// scanned is read from cache instead of scanned again.
+	// Default to [DefaultCacheTTL].
+	CacheTTL time.Duration
+
+	// CacheFailTTL the duration where the external link that failed to
+	// scanned is read from cache instead of scanned again.
+	// Default to [DefaultCacheFailTTL].
+	CacheFailTTL time.Duration
+
+	// IsVerbose set the level of default Logger to [slog.LevelDebug].
 	IsVerbose bool
 
 	// Insecure do not report error on server with invalid certificates.
 	Insecure bool
+
+	// NoCache do not read and write the scanned external links to cache.
+	// This option is ignored if Cache is set.
+	NoCache bool
+
+	// RefreshCache do not read the scanned external links from cache,
+	// but write the result of scan to cache.
+	RefreshCache bool
+
+	// SkipCode do not scan the links inside the "code" and "pre"
+	// elements.
+	SkipCode bool
+
+	// Resume continue the scan from the state in StateDir.
+	// If the state does not exist, the scan is started from beginning.
+	Resume bool
+
+	// Graph if true, the pages and links found during scan are stored
+	// in [Result.Graph].
+	// The graph is not available if Source is true.
+	Graph bool
+
+	// Inventory if true, all links found during scan, with their
+	// status, content type, size, response time, depth, and number of
+	// inbound links, are stored in [Result.Inventory].
+	// The inventory is not available if Source is true.
+	Inventory bool
+
+	// Sitemap if true, the internal HTML pages with status 200 that can
+	// be indexed by search engine are stored in [Result.Sitemap].
+	// The page is excluded if it has robots "noindex", or its
+	// canonical link refer to other page.
+	// The sitemap is not available if Source is true.
+	Sitemap bool
+
+	// Seo if true, the internal HTML pages with status 200 are audited
+	// for the common search engine optimization issues, and the issues
+	// are stored in [Result.Issues].
+	// The pages are always fetched and parsed, instead of read from
+	// cache, and the canonical links are scanned.
+	// This option cannot be used with StateDir and Source.
+	Seo bool
+
+	// A11y if true, the internal HTML pages with status 200 are audited
+	// for the common static accessibility issues, and the issues are
+	// stored in [Result.Issues].
+	// The pages are always fetched and parsed, instead of read from
+	// cache.
+	// The audit is not available if Source is true.
+	A11y bool
+
+	// MixedContent if true and the Url scheme is "https", the internal
+	// HTML pages are checked for subresources, like script, stylesheet,
+	// image, and iframe, that loaded using scheme "http".
+	// Each insecure subresource is stored in [Result.Issues], with
+	// note whether the same resource is available over https.
+	// The pages are always fetched and parsed, instead of read from
+	// cache.
+	MixedContent bool
+
+	// Headers if true, the security headers in the response of internal
+	// HTML pages with status 200 are audited, and the issues are stored
+	// in [Result.Issues], with the site-wide summary in
+	// [Result.HeadersSummary].
+	// The pages are always fetched, instead of read from cache.
+	// The audit is not available on local directory, since the pages
+	// are served by the HTTP server inside jarink instead of the
+	// website.
+	Headers bool
+
+	// Source scan the links inside the Markdown and AsciiDoc files,
+	// instead of HTML pages.
+	// The Url must be the path to local directory or file.
+	// The links to local files and its anchors are checked directly
+	// from the files, while the external links are fetched.
+	// The file without extension ".md" or ".markdown", for example
+	// "README", is parsed as AsciiDoc.
+	// This option cannot be used with PastResultFile.
+	Source bool
 }
 
 func (opts *Options) init() (err error) {
@@ -40,6 +208,39 @@ func (opts *Options) init() (err error) {
 	if err != nil {
 		return fmt.Errorf(`%s: invalid URL %q`, logp, opts.Url)
 	}
+	switch opts.scanUrl.Scheme {
+	case ``:
+		opts.localDir = opts.Url
+	case `file`:
+		opts.localDir = filepath.FromSlash(opts.scanUrl.Path)
+	}
+	if opts.localDir != `` {
+		var fi os.FileInfo
+		fi, err = os.Stat(opts.localDir)
+		if err != nil {
+			return fmt.Errorf(`%s: %w`, logp, err)
+		}
+		if !fi.IsDir() && !opts.Source {
+			return fmt.Errorf(`%s: %q is not a directory`, logp,
+				opts.localDir)
+		}
+		if opts.StateDir != `` {
+			return fmt.Errorf(`%s: StateDir cannot be used with`+
+				` local directory`, logp)
+		}
+		if opts.Headers {
+			return fmt.Errorf(`%s: Headers cannot be used with`+
+				` local directory`, logp)
+		}
+	} else if opts.Source {
+		return fmt.Errorf(`%s: Source require local directory or file`,
+			logp)
+	}
+	if opts.Source && opts.PastResultFile != `` {
+		return fmt.Errorf(`%s: Source cannot be used with`+
+			` PastResultFile`, logp)
+	}
+
 	opts.scanUrl.Path = strings.TrimSuffix(opts.scanUrl.Path, `/`)
 	opts.scanUrl.Fragment = ""
 	opts.scanUrl.RawFragment = ""
@@ -63,5 +264,50 @@ func (opts *Options) init() (err error) {
 		}
 		opts.ignoreStatus = append(opts.ignoreStatus, int(code))
 	}
+
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+	if opts.CacheFailTTL <= 0 {
+		opts.CacheFailTTL = DefaultCacheFailTTL
+	}
+
+	if opts.Resume && opts.StateDir == `` {
+		return fmt.Errorf(`%s: Resume require StateDir`, logp)
+	}
+	if opts.StateDir != `` && opts.DiskDir != `` {
+		return fmt.Errorf(`%s: StateDir cannot be used with DiskDir`,
+			logp)
+	}
+	if opts.Seo {
+		if opts.StateDir != `` {
+			return fmt.Errorf(`%s: Seo cannot be used with StateDir`,
+				logp)
+		}
+		if opts.Source {
+			return fmt.Errorf(`%s: Seo cannot be used with Source`,
+				logp)
+		}
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = DefaultMaxConcurrent
+	}
+
+	if opts.BaselineFile != `` {
+		opts.baseline, err = loadBaseline(opts.BaselineFile)
+		if err != nil {
+			return fmt.Errorf(`%s: %w`, logp, err)
+		}
+	}
+
+	if opts.Logger == nil {
+		var handlerOpts = &slog.HandlerOptions{
+			Level: slog.LevelWarn,
+		}
+		if opts.IsVerbose {
+			handlerOpts.Level = slog.LevelDebug
+		}
+		opts.Logger = slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+	}
 	return nil
 }
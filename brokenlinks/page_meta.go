@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"git.sr.ht/~shulhan/jarink"
+)
+
+// pageMeta contains the information about the HTML page, from its
+// content and HTTP response header.
+type pageMeta struct {
+	// canonical the href of element "link" with rel "canonical".
+	canonical string
+
+	// canonicalUrl the canonical link resolved to the page URL.
+	// It only set on SEO audit.
+	canonicalUrl string
+
+	// title the text of the first element "title".
+	title string
+
+	// description the content of the first element "meta" with name
+	// "description".
+	description string
+
+	// bodyHash the hex encoded SHA-256 of the text inside the page
+	// body, or empty if the body does not have any text.
+	bodyHash string
+
+	// lastModified the value of HTTP response header "Last-Modified".
+	lastModified string
+
+	// noIndex true if the page has element "meta" with name "robots",
+	// or HTTP response header "X-Robots-Tag", that contains "noindex"
+	// or "none".
+	noIndex bool
+
+	// listNav contains the URL of internal links inside the element
+	// "nav".
+	// It only set on SEO audit.
+	listNav []string
+
+	// nTitle the number of element "title".
+	nTitle int
+
+	// nH1 the number of element "h1".
+	nH1 int
+}
+
+// newPageMeta create pageMeta from the page content and its HTTP response
+// header.
+func newPageMeta(content []byte, header http.Header) (meta pageMeta) {
+	meta = extractMeta(content)
+	meta.parseHeader(header)
+	return meta
+}
+
+// metaFromCache create pageMeta from the page in the cache.
+func metaFromCache(scannedLink *jarink.ScannedLink) pageMeta {
+	return pageMeta{
+		canonical:    scannedLink.Canonical,
+		lastModified: scannedLink.LastModified,
+		noIndex:      scannedLink.NoIndex,
+	}
+}
+
+// parseHeader set the lastModified and noIndex from the HTTP response
+// header.
+func (meta *pageMeta) parseHeader(header http.Header) {
+	meta.lastModified = header.Get(`Last-Modified`)
+	for _, val := range header.Values(`X-Robots-Tag`) {
+		if isNoIndex(val) {
+			meta.noIndex = true
+		}
+	}
+}
+
+// parseHeadToken set the canonical, noIndex, and description from the
+// element "link" or "meta".
+func (meta *pageMeta) parseHeadToken(token html.Token) {
+	switch token.DataAtom {
+	case atom.Link:
+		var rel, _ = attrValue(token.Attr, `rel`)
+		if !hasToken(rel, `canonical`) || meta.canonical != `` {
+			return
+		}
+		meta.canonical, _ = attrValue(token.Attr, `href`)
+		meta.canonical = strings.TrimSpace(meta.canonical)
+
+	case atom.Meta:
+		var name, _ = attrValue(token.Attr, `name`)
+		var val, _ = attrValue(token.Attr, `content`)
+		switch strings.ToLower(name) {
+		case `robots`:
+			if isNoIndex(val) {
+				meta.noIndex = true
+			}
+		case `description`:
+			if meta.description == `` {
+				meta.description = strings.TrimSpace(val)
+			}
+		}
+	}
+}
+
+// extractMeta parse the HTML content and return the canonical link,
+// robots meta, title, description, number of "h1", and the hash of text
+// in the body.
+func extractMeta(content []byte) (meta pageMeta) {
+	var (
+		tokenizer = html.NewTokenizer(bytes.NewReader(content))
+
+		// nSkip the number of opened elements where the text is not
+		// part of page content, for example "script" and "style".
+		nSkip int
+
+		inHead  bool
+		inTitle bool
+
+		title    strings.Builder
+		bodyText strings.Builder
+	)
+	for {
+		var tokenType = tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			meta.title = strings.Join(strings.Fields(title.String()), ` `)
+			var text = strings.Fields(bodyText.String())
+			if len(text) != 0 {
+				var sum = sha256.Sum256([]byte(strings.Join(text, ` `)))
+				meta.bodyHash = hex.EncodeToString(sum[:])
+			}
+			return meta
+
+		case html.TextToken:
+			switch {
+			case inTitle:
+				if meta.nTitle == 1 {
+					title.Write(tokenizer.Text())
+				}
+			case inHead || nSkip > 0:
+			default:
+				bodyText.Write(tokenizer.Text())
+				bodyText.WriteByte(' ')
+			}
+
+		case html.EndTagToken:
+			var name, _ = tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Head:
+				inHead = false
+			case atom.Title:
+				inTitle = false
+			case atom.Script, atom.Style, atom.Template, atom.Noscript:
+				if nSkip > 0 {
+					nSkip--
+				}
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			var token = tokenizer.Token()
+			var isStart = tokenType == html.StartTagToken
+			switch token.DataAtom {
+			case atom.Head:
+				inHead = isStart
+			case atom.Body:
+				inHead = false
+			case atom.Title:
+				inTitle = isStart
+				meta.nTitle++
+			case atom.H1:
+				meta.nH1++
+			case atom.Script, atom.Style, atom.Template, atom.Noscript:
+				if isStart {
+					nSkip++
+				}
+			case atom.Link, atom.Meta:
+				meta.parseHeadToken(token)
+			}
+		}
+	}
+}
+
+// hasToken return true if the space separated list contains the token,
+// case insensitive.
+func hasToken(list, token string) bool {
+	for _, field := range strings.Fields(list) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNoIndex return true if the comma separated robots directives contains
+// "noindex" or "none".
+// The directive in "X-Robots-Tag" may be prefixed with the name of
+// crawler, for example "googlebot: noindex".
+func isNoIndex(directives string) bool {
+	for _, directive := range strings.Split(directives, `,`) {
+		var _, after, found = strings.Cut(directive, `:`)
+		if found {
+			directive = after
+		}
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == `noindex` || directive == `none` {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,9 @@
 package brokenlinks
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
 	"slices"
 	"strings"
 )
@@ -13,13 +16,62 @@ import (
 type Broken struct {
 	Link  string `json:"link"`
 	Error string `json:"error,omitempty"`
-	Code  int    `json:"code"`
+
+	// Text of the anchor or the alternate text of image.
+	Text string `json:"text,omitempty"`
+
+	// Element and attribute where the link found, either "a@href" or
+	// "img@src".
+	Element string `json:"element,omitempty"`
+
+	Code int `json:"code"`
+
+	// Line and Column of the first element with the link inside the
+	// HTML source of the page, start from 1.
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+
+	// Count number of the same link found inside the page.
+	Count int `json:"count,omitempty"`
+
+	// Suggestions contains the internal links that has been scanned
+	// successfully and likely to be the intended target of the broken
+	// internal link, ordered from the most likely.
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // Result store the result of scanning for broken links.
 type Result struct {
 	// BrokenLinks store the page and its broken links.
 	BrokenLinks map[string][]Broken `json:"broken_links"`
+
+	// Suppressed store the page and its broken links that match with
+	// one of the entries in [Options.BaselineFile].
+	Suppressed map[string][]Broken `json:"suppressed,omitempty"`
+
+	// StaleBaseline contains the entries in [Options.BaselineFile] that
+	// does not match with any broken links or has been expired.
+	StaleBaseline []BaselineEntry `json:"stale_baseline,omitempty"`
+
+	// Graph contains the pages and links found during scan, if
+	// [Options.Graph] is true.
+	Graph *Graph `json:"graph,omitempty"`
+
+	// Inventory contains all links found during scan, if
+	// [Options.Inventory] is true.
+	Inventory Inventory `json:"inventory,omitempty"`
+
+	// Sitemap contains the pages that can be indexed by search engine,
+	// if [Options.Sitemap] is true.
+	Sitemap Sitemap `json:"sitemap,omitempty"`
+
+	// Issues store the page and its issues found by audit, for example
+	// if [Options.Seo] is true.
+	Issues map[string][]Issue `json:"issues,omitempty"`
+
+	// HeadersSummary contains the number of pages audited and the
+	// number of pages with issue by rule, if [Options.Headers] is true.
+	HeadersSummary *HeadersSummary `json:"headers_summary,omitempty"`
 }
 
 func newResult() *Result {
@@ -28,9 +80,43 @@ func newResult() *Result {
 	}
 }
 
+// LoadResult load the result of past scan from JSON file.
+func LoadResult(file string) (result *Result, err error) {
+	var logp = `LoadResult`
+
+	result, err = loadResult(file)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	return result, nil
+}
+
+func loadResult(file string) (result *Result, err error) {
+	var content []byte
+
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	result = newResult()
+	err = json.Unmarshal(content, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (result *Result) sort() {
-	for _, listBroken := range result.BrokenLinks {
-		slices.SortFunc(listBroken, func(a, b Broken) int {
+	sortBrokenLinks(result.BrokenLinks)
+	sortBrokenLinks(result.Suppressed)
+	result.Sitemap.sort()
+	sortIssues(result.Issues)
+}
+
+func sortBrokenLinks(brokenLinks map[string][]Broken) {
+	for _, listBroken := range brokenLinks {
+		slices.SortStableFunc(listBroken, func(a, b Broken) int {
 			return strings.Compare(a.Link, b.Link)
 		})
 	}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// robotsTxt contains the rules in robots.txt for all crawlers, the group
+// with user-agent "*", and the sitemaps.
+type robotsTxt struct {
+	listRule    []robotsRule
+	listSitemap []robotsSitemap
+}
+
+// robotsRule the "Allow" or "Disallow" rule in robots.txt.
+type robotsRule struct {
+	// re the pattern converted into regular expression.
+	re *regexp.Regexp
+
+	pattern string
+	allow   bool
+}
+
+// robotsSitemap the "Sitemap" in robots.txt and its line number.
+type robotsSitemap struct {
+	url  string
+	line int
+}
+
+// parseRobotsTxt parse the content of robots.txt as defined in RFC 9309.
+// Only the rules in the group with user-agent "*" are stored.
+func parseRobotsTxt(content []byte) (robots *robotsTxt) {
+	robots = &robotsTxt{}
+
+	var (
+		scanner = bufio.NewScanner(bytes.NewReader(content))
+
+		// isAgent true if the previous line is user-agent, so the
+		// next user-agent is in the same group.
+		isAgent bool
+
+		// isAll true if the current group has user-agent "*".
+		isAll bool
+
+		lineNum int
+	)
+	for scanner.Scan() {
+		lineNum++
+		var line, _, _ = strings.Cut(scanner.Text(), `#`)
+		var key, val, ok = strings.Cut(line, `:`)
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+
+		switch key {
+		case `user-agent`:
+			if !isAgent {
+				isAll = false
+			}
+			if val == `*` {
+				isAll = true
+			}
+			isAgent = true
+
+		case `allow`, `disallow`:
+			isAgent = false
+			if !isAll || val == `` {
+				// The empty rule match nothing.
+				continue
+			}
+			robots.listRule = append(robots.listRule, robotsRule{
+				re:      robotsPattern(val),
+				pattern: val,
+				allow:   key == `allow`,
+			})
+
+		case `sitemap`:
+			robots.listSitemap = append(robots.listSitemap,
+				robotsSitemap{
+					url:  val,
+					line: lineNum,
+				})
+		}
+	}
+	return robots
+}
+
+// robotsPattern convert the path pattern in robots.txt into regular
+// expression, where "*" match any characters and "$" at the end match the
+// end of path.
+func robotsPattern(pattern string) *regexp.Regexp {
+	var isEnd bool
+	pattern, isEnd = strings.CutSuffix(pattern, `$`)
+	var expr = regexp.QuoteMeta(pattern)
+	expr = `^` + strings.ReplaceAll(expr, `\*`, `.*`)
+	if isEnd {
+		expr += `$`
+	}
+	return regexp.MustCompile(expr)
+}
+
+// isAllowed return true if the path, including its query, is allowed to
+// be crawled.
+// The rule with the longest pattern that match the path is used, and
+// "Allow" is preferred if both rules has the same length.
+func (robots *robotsTxt) isAllowed(path string) bool {
+	var (
+		allow  = true
+		maxLen = -1
+	)
+	for _, rule := range robots.listRule {
+		if !rule.re.MatchString(path) {
+			continue
+		}
+		var ruleLen = len(rule.pattern)
+		if ruleLen > maxLen || (ruleLen == maxLen && rule.allow) {
+			maxLen = ruleLen
+			allow = rule.allow
+		}
+	}
+	return allow
+}
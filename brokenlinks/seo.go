@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// List of the recommended length of page title, in characters.
+const (
+	seoTitleMinLength = 10
+	seoTitleMaxLength = 60
+)
+
+// List of rules reported by SEO audit.
+const (
+	SeoCanonicalMissing     = `seo/canonical-missing`
+	SeoCanonicalStatus      = `seo/canonical-status`
+	SeoContentDuplicate     = `seo/content-duplicate`
+	SeoDescriptionMissing   = `seo/description-missing`
+	SeoDescriptionDuplicate = `seo/description-duplicate`
+	SeoH1Missing            = `seo/h1-missing`
+	SeoH1Multiple           = `seo/h1-multiple`
+	SeoNavNoIndex           = `seo/nav-noindex`
+	SeoTitleDuplicate       = `seo/title-duplicate`
+	SeoTitleLength          = `seo/title-length`
+	SeoTitleMissing         = `seo/title-missing`
+	SeoTitleMultiple        = `seo/title-multiple`
+)
+
+// seoPage contains the scanned page for SEO audit.
+type seoPage struct {
+	url  string
+	meta pageMeta
+
+	// canonicalStatus the HTTP status code of canonical link, or 0 if
+	// the page does not have canonical link or its has not been
+	// scanned.
+	canonicalStatus int
+
+	// isIndexable true if the page does not have robots "noindex" and
+	// its canonical link refer to itself.
+	isIndexable bool
+}
+
+// auditSeo audit the list of pages and return the issues found on each
+// page, by its URL.
+func auditSeo(listPage []seoPage) (issues map[string][]Issue) {
+	issues = map[string][]Issue{}
+
+	var add = func(page string, issue Issue) {
+		issues[page] = append(issues[page], issue)
+	}
+
+	slices.SortFunc(listPage, func(a, b seoPage) int {
+		return strings.Compare(a.url, b.url)
+	})
+
+	var (
+		noIndex      = map[string]bool{}
+		titlePages   = map[string][]string{}
+		descPages    = map[string][]string{}
+		contentPages = map[string][]string{}
+	)
+	for _, page := range listPage {
+		noIndex[page.url] = page.meta.noIndex
+		if !page.isIndexable {
+			continue
+		}
+		if page.meta.title != `` {
+			titlePages[page.meta.title] = append(
+				titlePages[page.meta.title], page.url)
+		}
+		if page.meta.description != `` {
+			descPages[page.meta.description] = append(
+				descPages[page.meta.description], page.url)
+		}
+		if page.meta.bodyHash != `` {
+			contentPages[page.meta.bodyHash] = append(
+				contentPages[page.meta.bodyHash], page.url)
+		}
+	}
+
+	for _, page := range listPage {
+		var meta = page.meta
+
+		switch {
+		case meta.title == ``:
+			add(page.url, Issue{
+				Rule:    SeoTitleMissing,
+				Message: `page does not have title`,
+			})
+		case meta.nTitle > 1:
+			add(page.url, Issue{
+				Rule: SeoTitleMultiple,
+				Message: fmt.Sprintf(`page has %d title elements`,
+					meta.nTitle),
+			})
+		}
+		var titleLength = utf8.RuneCountInString(meta.title)
+		if meta.title != `` && (titleLength < seoTitleMinLength ||
+			titleLength > seoTitleMaxLength) {
+			add(page.url, Issue{
+				Rule: SeoTitleLength,
+				Message: fmt.Sprintf(`title has %d characters,`+
+					` should be between %d and %d`,
+					titleLength, seoTitleMinLength,
+					seoTitleMaxLength),
+			})
+		}
+
+		if meta.description == `` {
+			add(page.url, Issue{
+				Rule:    SeoDescriptionMissing,
+				Message: `page does not have meta description`,
+			})
+		}
+
+		switch {
+		case meta.nH1 == 0:
+			add(page.url, Issue{
+				Rule:    SeoH1Missing,
+				Message: `page does not have h1`,
+			})
+		case meta.nH1 > 1:
+			add(page.url, Issue{
+				Rule: SeoH1Multiple,
+				Message: fmt.Sprintf(`page has %d h1 elements`,
+					meta.nH1),
+			})
+		}
+
+		switch {
+		case meta.canonical == ``:
+			add(page.url, Issue{
+				Rule:    SeoCanonicalMissing,
+				Message: `page does not have canonical link`,
+			})
+		case page.canonicalStatus != 0 &&
+			page.canonicalStatus != http.StatusOK:
+			add(page.url, Issue{
+				Rule: SeoCanonicalStatus,
+				Message: fmt.Sprintf(`canonical link return`+
+					` status %d`, page.canonicalStatus),
+				Link: meta.canonicalUrl,
+			})
+		}
+
+		var listNoIndex []string
+		for _, nav := range meta.listNav {
+			if noIndex[nav] && !slices.Contains(listNoIndex, nav) {
+				listNoIndex = append(listNoIndex, nav)
+			}
+		}
+		for _, nav := range listNoIndex {
+			add(page.url, Issue{
+				Rule:    SeoNavNoIndex,
+				Message: `navigation link to page with robots noindex`,
+				Link:    nav,
+			})
+		}
+
+		if !page.isIndexable {
+			continue
+		}
+		var other = firstOther(titlePages[meta.title], page.url)
+		if other != `` {
+			add(page.url, Issue{
+				Rule:    SeoTitleDuplicate,
+				Message: `title is duplicate with other page`,
+				Link:    other,
+			})
+		}
+		other = firstOther(descPages[meta.description], page.url)
+		if other != `` {
+			add(page.url, Issue{
+				Rule:    SeoDescriptionDuplicate,
+				Message: `meta description is duplicate with other page`,
+				Link:    other,
+			})
+		}
+		other = firstOther(contentPages[meta.bodyHash], page.url)
+		if other != `` {
+			add(page.url, Issue{
+				Rule:    SeoContentDuplicate,
+				Message: `content is duplicate with other page`,
+				Link:    other,
+			})
+		}
+	}
+	return issues
+}
+
+// firstOther return the first page in the list that is not the page
+// itself, or empty string if there is none.
+func firstOther(listPage []string, page string) string {
+	for _, other := range listPage {
+		if other != page {
+			return other
+		}
+	}
+	return ``
+}
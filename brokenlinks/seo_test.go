@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_seo(t *testing.T) {
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     filepath.Join(`testdata`, `seo`),
+		NoCache: true,
+		Seo:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		pageAbout = `testdata/seo/about.html`
+		pageCopy  = `testdata/seo/copy.html`
+		pageDraft = `testdata/seo/draft.html`
+		pageMoved = `testdata/seo/moved.html`
+		pageOther = `testdata/seo/other.html`
+		pageGone  = `testdata/seo/gone.html`
+	)
+	var expIssues = map[string][]brokenlinks.Issue{
+		pageAbout: {{
+			Rule:    brokenlinks.SeoDescriptionMissing,
+			Message: `page does not have meta description`,
+		}, {
+			Rule:    brokenlinks.SeoH1Multiple,
+			Message: `page has 2 h1 elements`,
+		}, {
+			Rule:    brokenlinks.SeoTitleLength,
+			Message: `title has 5 characters, should be between 10 and 60`,
+		}},
+		pageCopy: {{
+			Rule:    brokenlinks.SeoContentDuplicate,
+			Message: `content is duplicate with other page`,
+			Link:    pageOther,
+		}, {
+			Rule:    brokenlinks.SeoDescriptionDuplicate,
+			Message: `meta description is duplicate with other page`,
+			Link:    pageOther,
+		}, {
+			Rule:    brokenlinks.SeoTitleDuplicate,
+			Message: `title is duplicate with other page`,
+			Link:    pageOther,
+		}},
+		pageDraft: {{
+			Rule:    brokenlinks.SeoCanonicalMissing,
+			Message: `page does not have canonical link`,
+		}, {
+			Rule:    brokenlinks.SeoDescriptionMissing,
+			Message: `page does not have meta description`,
+		}, {
+			Rule:    brokenlinks.SeoH1Missing,
+			Message: `page does not have h1`,
+		}, {
+			Rule:    brokenlinks.SeoTitleMissing,
+			Message: `page does not have title`,
+		}},
+		`testdata/seo/index.html`: {{
+			Rule:    brokenlinks.SeoNavNoIndex,
+			Message: `navigation link to page with robots noindex`,
+			Link:    pageDraft,
+		}},
+		pageMoved: {{
+			Rule:    brokenlinks.SeoCanonicalStatus,
+			Message: `canonical link return status 404`,
+			Link:    pageGone,
+		}},
+		pageOther: {{
+			Rule:    brokenlinks.SeoContentDuplicate,
+			Message: `content is duplicate with other page`,
+			Link:    pageCopy,
+		}, {
+			Rule:    brokenlinks.SeoDescriptionDuplicate,
+			Message: `meta description is duplicate with other page`,
+			Link:    pageCopy,
+		}, {
+			Rule:    brokenlinks.SeoTitleDuplicate,
+			Message: `title is duplicate with other page`,
+			Link:    pageCopy,
+		}},
+	}
+	test.Assert(t, `Issues`, expIssues, got.Issues)
+
+	// The canonical link is scanned, so its reported as broken link
+	// too.
+	var expBroken = map[string][]brokenlinks.Broken{
+		pageMoved: {{
+			Link:    pageGone,
+			Element: `link@href`,
+			Code:    http.StatusNotFound,
+			Count:   1,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, expBroken, got.BrokenLinks)
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SitemapMaxUrl the maximum number of URLs in one sitemap file, as
+// defined by the sitemaps protocol.
+const SitemapMaxUrl = 50000
+
+// sitemapXmlns the XML namespace of sitemaps protocol.
+const sitemapXmlns = `http://www.sitemaps.org/schemas/sitemap/0.9`
+
+// Sitemap contains the pages that can be indexed by search engine,
+// sorted by Loc.
+type Sitemap []SitemapUrl
+
+// SitemapUrl the page in the [Sitemap].
+type SitemapUrl struct {
+	// Loc the URL of page, or the absolute path of page, for example
+	// "/docs/", when scanning local directory.
+	Loc string `json:"loc"`
+
+	// LastMod the last modification time of page in W3C Datetime
+	// format, from the HTTP response header "Last-Modified".
+	LastMod string `json:"lastmod,omitempty"`
+}
+
+// w3cDatetime convert the value of HTTP header "Last-Modified" into W3C
+// Datetime format.
+// It return empty string if the value is not valid HTTP time.
+func w3cDatetime(lastModified string) string {
+	var t, err = http.ParseTime(lastModified)
+	if err != nil {
+		return ``
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Write the sitemap into file "sitemap.xml" inside the directory dir.
+// The Loc that is not absolute URL is joined with the baseUrl, for
+// example "/docs/" with base URL "https://web.tld/blog" become
+// "https://web.tld/blog/docs/".
+//
+// If the sitemap contains more than [SitemapMaxUrl] URLs, the sitemap is
+// split into files "sitemap-1.xml", "sitemap-2.xml", and so on, and the
+// file "sitemap.xml" become the sitemap index that refer to them using
+// the baseUrl.
+//
+// It return the list of files written.
+func (sitemap Sitemap) Write(dir, baseUrl string) (files []string, err error) {
+	var logp = `Write`
+
+	var base *url.URL
+	if baseUrl != `` {
+		base, err = url.Parse(baseUrl)
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, err)
+		}
+		if !base.IsAbs() {
+			return nil, fmt.Errorf(`%s: base URL %q is not absolute`,
+				logp, baseUrl)
+		}
+	}
+
+	var listUrl = make([]SitemapUrl, 0, len(sitemap))
+	for _, item := range sitemap {
+		var loc *url.URL
+		loc, err = url.Parse(item.Loc)
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, err)
+		}
+		if !loc.IsAbs() {
+			if base == nil {
+				return nil, fmt.Errorf(`%s: %q: missing base URL`,
+					logp, item.Loc)
+			}
+			loc = base.JoinPath(loc.Path)
+		}
+		item.Loc = loc.String()
+		listUrl = append(listUrl, item)
+	}
+
+	var file = filepath.Join(dir, `sitemap.xml`)
+	if len(listUrl) <= SitemapMaxUrl {
+		err = writeFile(file, func(w io.Writer) error {
+			return writeUrlset(w, listUrl)
+		})
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, err)
+		}
+		return []string{file}, nil
+	}
+	if base == nil {
+		return nil, fmt.Errorf(`%s: missing base URL for sitemap index`,
+			logp)
+	}
+
+	var listLoc []string
+	for chunk := range slices.Chunk(listUrl, SitemapMaxUrl) {
+		var name = fmt.Sprintf(`sitemap-%d.xml`, len(listLoc)+1)
+		var partFile = filepath.Join(dir, name)
+		err = writeFile(partFile, func(w io.Writer) error {
+			return writeUrlset(w, chunk)
+		})
+		if err != nil {
+			return nil, fmt.Errorf(`%s: %w`, logp, err)
+		}
+		files = append(files, partFile)
+		listLoc = append(listLoc,
+			base.JoinPath(name).String())
+	}
+	err = writeFile(file, func(w io.Writer) error {
+		return writeSitemapIndex(w, listLoc)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	return append([]string{file}, files...), nil
+}
+
+// writeUrlset write the list of URL as sitemap "urlset".
+func writeUrlset(w io.Writer, listUrl []SitemapUrl) error {
+	var bw = bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	fmt.Fprintf(bw, "<urlset xmlns=%q>\n", sitemapXmlns)
+	for _, item := range listUrl {
+		bw.WriteString("  <url>\n")
+		fmt.Fprintf(bw, "    <loc>%s</loc>\n", xmlEscape(item.Loc))
+		if item.LastMod != `` {
+			fmt.Fprintf(bw, "    <lastmod>%s</lastmod>\n",
+				item.LastMod)
+		}
+		bw.WriteString("  </url>\n")
+	}
+	bw.WriteString("</urlset>\n")
+	return bw.Flush()
+}
+
+// writeSitemapIndex write the list of sitemap location as
+// "sitemapindex".
+func writeSitemapIndex(w io.Writer, listLoc []string) error {
+	var bw = bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	fmt.Fprintf(bw, "<sitemapindex xmlns=%q>\n", sitemapXmlns)
+	for _, loc := range listLoc {
+		bw.WriteString("  <sitemap>\n")
+		fmt.Fprintf(bw, "    <loc>%s</loc>\n", xmlEscape(loc))
+		bw.WriteString("  </sitemap>\n")
+	}
+	bw.WriteString("</sitemapindex>\n")
+	return bw.Flush()
+}
+
+// writeFile create the file and write its content using function write.
+func writeFile(file string, write func(io.Writer) error) (err error) {
+	var out *os.File
+	out, err = os.Create(file)
+	if err != nil {
+		return err
+	}
+	err = write(out)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// sort the sitemap by Loc.
+func (sitemap Sitemap) sort() {
+	slices.SortFunc(sitemap, func(a, b SitemapUrl) int {
+		return strings.Compare(a.Loc, b.Loc)
+	})
+}
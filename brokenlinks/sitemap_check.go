@@ -0,0 +1,708 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatusInvalidSitemap status for the robots.txt, sitemap, or URL in the
+// sitemap that does not follow the sitemaps protocol, or the URL that
+// should not be listed in the sitemap.
+const StatusInvalidSitemap = 701
+
+// List of limits defined by the sitemaps protocol.
+const (
+	// sitemapMaxSize the maximum size of uncompressed sitemap, 50MiB.
+	sitemapMaxSize = 50 * 1024 * 1024
+
+	// sitemapMaxLocLength the maximum length of URL in sitemap.
+	sitemapMaxLocLength = 2048
+
+	// robotsMaxSize the maximum size of robots.txt that is parsed, as
+	// recommended by RFC 9309.
+	robotsMaxSize = 500 * 1024
+)
+
+// sitemapChangeFreq the valid values of element "changefreq".
+var sitemapChangeFreq = []string{
+	`always`,
+	`hourly`,
+	`daily`,
+	`weekly`,
+	`monthly`,
+	`yearly`,
+	`never`,
+}
+
+// sitemapFile the sitemap to be checked.
+type sitemapFile struct {
+	// url of sitemap.
+	url string
+
+	// parent the robots.txt or the sitemap index where the sitemap
+	// found.
+	parent string
+
+	// line number of sitemap inside the parent.
+	line int
+
+	// fromRobots true if the sitemap, or its sitemap index, found in
+	// robots.txt, so it can contains URLs outside its directory.
+	fromRobots bool
+
+	// inIndex true if the sitemap found in the sitemap index.
+	inIndex bool
+}
+
+// sitemapLoc the URL in the sitemap to be checked.
+type sitemapLoc struct {
+	url     *url.URL
+	sitemap string
+	line    int
+}
+
+// sitemapEntry the element "url" in "urlset" or the element "sitemap" in
+// "sitemapindex".
+type sitemapEntry struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// sitemapChecker check the robots.txt, the sitemaps, and the URLs in the
+// sitemaps of the website.
+type sitemapChecker struct {
+	ctx context.Context
+
+	log *slog.Logger
+
+	// httpc the client to fetch the sitemaps and their URLs, where the
+	// redirect is not followed.
+	httpc *http.Client
+
+	// robotsc the client to fetch the robots.txt, where the redirect is
+	// followed up to 10 times, as RFC 9309 require crawler to follow at
+	// least five redirects.
+	robotsc *http.Client
+
+	robots *robotsTxt
+
+	result *Result
+
+	// siteUrl the scheme and host of website.
+	siteUrl *url.URL
+
+	// seenSitemap the sitemap that has been queued.
+	seenSitemap map[string]bool
+
+	// seenLoc the URL in sitemaps that has been found.
+	seenLoc map[string]bool
+
+	opts Options
+
+	queue   []sitemapFile
+	listLoc []sitemapLoc
+
+	// mtx protect the result when checking the URLs concurrently.
+	mtx sync.Mutex
+}
+
+// CheckSitemap check the robots.txt and the sitemaps of the website in
+// [Options.Url], and the URLs listed in the sitemaps.
+//
+// The sitemaps are read from the "Sitemap" in robots.txt, or from
+// "/sitemap.xml" if robots.txt does not have any.
+// The sitemap index is followed to its sitemaps.
+// Each sitemap is validated against the sitemaps protocol: the size,
+// number of URLs, XML namespace, the length and host of URL, and the
+// format of "lastmod", "changefreq", and "priority".
+// Each URL in the sitemaps must return status 200 without redirect, does
+// not have robots "noindex", and is not disallowed by robots.txt.
+//
+// The findings are reported in the [Result.BrokenLinks], where the key is
+// the robots.txt or the sitemap where the link found.
+// The link that cannot be fetched has the HTTP status code or
+// [StatusBadLink], while other findings has the code
+// [StatusInvalidSitemap] or the HTTP status code, with the reason in
+// [Broken.Error].
+//
+// Only the fields Url, Insecure, MaxConcurrent, Logger, IsVerbose, and
+// OnBroken in opts are used.
+func CheckSitemap(ctx context.Context, opts Options) (result *Result, err error) {
+	var logp = `CheckSitemap`
+
+	var siteUrl *url.URL
+	siteUrl, err = url.Parse(opts.Url)
+	if err != nil || (siteUrl.Scheme != `http` && siteUrl.Scheme != `https`) {
+		return nil, fmt.Errorf(`%s: %q is not website URL`, logp,
+			opts.Url)
+	}
+	err = opts.init()
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	var checker = &sitemapChecker{
+		ctx:         ctx,
+		log:         opts.Logger,
+		httpc:       newHttpClient(opts.Insecure),
+		result:      newResult(),
+		opts:        opts,
+		seenSitemap: map[string]bool{},
+		seenLoc:     map[string]bool{},
+		siteUrl: &url.URL{
+			Scheme: siteUrl.Scheme,
+			Host:   siteUrl.Host,
+		},
+	}
+	checker.robotsc = &http.Client{
+		Transport: checker.httpc.Transport,
+	}
+	// The redirect is reported, not followed.
+	checker.httpc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	err = checker.checkRobots()
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	for len(checker.queue) != 0 && ctx.Err() == nil {
+		var sitemap = checker.queue[0]
+		checker.queue = checker.queue[1:]
+		checker.checkSitemap(sitemap)
+	}
+	checker.checkLocs()
+
+	checker.result.sort()
+	if ctx.Err() != nil {
+		return checker.result, fmt.Errorf(`%s: %w`, logp, ErrInterrupted)
+	}
+	return checker.result, nil
+}
+
+// report the finding on link inside the page.
+func (checker *sitemapChecker) report(page string, broken Broken) {
+	checker.mtx.Lock()
+	defer checker.mtx.Unlock()
+
+	if checker.opts.OnBroken != nil {
+		checker.opts.OnBroken(page, broken)
+		return
+	}
+	checker.result.BrokenLinks[page] = append(
+		checker.result.BrokenLinks[page], broken)
+}
+
+// fetch the link using HTTP GET with the client httpc.
+func (checker *sitemapChecker) fetch(httpc *http.Client, link string) (
+	httpResp *http.Response, err error,
+) {
+	var httpReq *http.Request
+	httpReq, err = http.NewRequestWithContext(checker.ctx, http.MethodGet,
+		link, nil)
+	if err != nil {
+		return nil, err
+	}
+	var start = time.Now()
+	httpResp, err = httpc.Do(httpReq)
+	var attrs = []any{
+		slog.String(`method`, http.MethodGet),
+		slog.String(`url`, link),
+		slog.Duration(`duration`, time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String(`error`, err.Error()))
+		checker.log.Info(`fetch`, attrs...)
+		return nil, err
+	}
+	attrs = append(attrs, slog.Int(`status`, httpResp.StatusCode))
+	checker.log.Info(`fetch`, attrs...)
+	return httpResp, nil
+}
+
+// checkRobots fetch and parse the robots.txt, and queue the sitemaps in
+// it.
+// It return an error if the server cannot be reached.
+func (checker *sitemapChecker) checkRobots() (err error) {
+	var robotsUrl = checker.siteUrl.JoinPath(`robots.txt`).String()
+
+	var httpResp *http.Response
+	httpResp, err = checker.fetch(checker.robotsc, robotsUrl)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	checker.robots = &robotsTxt{}
+	switch {
+	case httpResp.StatusCode == http.StatusOK:
+		var content []byte
+		content, err = io.ReadAll(io.LimitReader(httpResp.Body,
+			robotsMaxSize))
+		if err != nil {
+			return err
+		}
+		checker.robots = parseRobotsTxt(content)
+
+	case httpResp.StatusCode >= http.StatusBadRequest &&
+		httpResp.StatusCode < http.StatusInternalServerError:
+		// The robots.txt is not available, all URLs are allowed.
+
+	default:
+		checker.report(robotsUrl, Broken{
+			Link:  robotsUrl,
+			Code:  httpResp.StatusCode,
+			Error: `unexpected status of robots.txt`,
+		})
+	}
+
+	for _, rsitemap := range checker.robots.listSitemap {
+		var sitemapUrl *url.URL
+		sitemapUrl, err = url.Parse(rsitemap.url)
+		if err != nil || !sitemapUrl.IsAbs() {
+			checker.report(robotsUrl, Broken{
+				Link:  rsitemap.url,
+				Code:  StatusInvalidSitemap,
+				Error: `sitemap URL is not absolute`,
+				Line:  rsitemap.line,
+			})
+			continue
+		}
+		checker.queueSitemap(sitemapFile{
+			url:        sitemapUrl.String(),
+			parent:     robotsUrl,
+			line:       rsitemap.line,
+			fromRobots: true,
+		})
+	}
+	if len(checker.queue) == 0 {
+		checker.queueSitemap(sitemapFile{
+			url:    checker.siteUrl.JoinPath(`sitemap.xml`).String(),
+			parent: robotsUrl,
+		})
+	}
+	return nil
+}
+
+// queueSitemap add the sitemap to be checked, if its not queued before.
+func (checker *sitemapChecker) queueSitemap(sitemap sitemapFile) {
+	if checker.seenSitemap[sitemap.url] {
+		return
+	}
+	checker.seenSitemap[sitemap.url] = true
+	checker.queue = append(checker.queue, sitemap)
+}
+
+// checkSitemap fetch and validate the sitemap or sitemap index.
+func (checker *sitemapChecker) checkSitemap(sitemap sitemapFile) {
+	var content, err = checker.readSitemap(sitemap)
+	if err != nil {
+		var broken = Broken{
+			Link:  sitemap.url,
+			Code:  StatusBadLink,
+			Error: err.Error(),
+			Line:  sitemap.line,
+		}
+		var errStatus *sitemapStatusError
+		if errors.As(err, &errStatus) {
+			broken.Code = errStatus.code
+			broken.Error = errStatus.msg
+		}
+		checker.report(sitemap.parent, broken)
+		return
+	}
+	if len(content) > sitemapMaxSize {
+		checker.report(sitemap.url, Broken{
+			Link: sitemap.url,
+			Code: StatusInvalidSitemap,
+			Error: fmt.Sprintf(`sitemap size is larger than %d bytes`,
+				sitemapMaxSize),
+		})
+		return
+	}
+
+	var (
+		dec      = xml.NewDecoder(bytes.NewReader(content))
+		root     xml.StartElement
+		nentry   int
+		hasRoot  bool
+		errToken error
+	)
+	for {
+		var token xml.Token
+		token, errToken = dec.Token()
+		if errToken != nil {
+			break
+		}
+		var start, ok = token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !hasRoot {
+			root = start
+			hasRoot = true
+			if !checker.checkRoot(sitemap, root) {
+				return
+			}
+			continue
+		}
+
+		var line, _ = dec.InputPos()
+		var entry sitemapEntry
+		errToken = dec.DecodeElement(&entry, &start)
+		if errToken != nil {
+			break
+		}
+		var expName = `url`
+		if root.Name.Local == `sitemapindex` {
+			expName = `sitemap`
+		}
+		if start.Name.Local != expName {
+			checker.report(sitemap.url, Broken{
+				Link: sitemap.url,
+				Code: StatusInvalidSitemap,
+				Error: fmt.Sprintf(`unknown element %q in %q`,
+					start.Name.Local, root.Name.Local),
+				Line: line,
+			})
+			continue
+		}
+		nentry++
+		checker.checkEntry(sitemap, root.Name.Local, entry, line)
+	}
+	if errToken != nil && !errors.Is(errToken, io.EOF) {
+		checker.report(sitemap.url, Broken{
+			Link:  sitemap.url,
+			Code:  StatusInvalidSitemap,
+			Error: `invalid XML: ` + errToken.Error(),
+		})
+		return
+	}
+	if !hasRoot {
+		checker.report(sitemap.url, Broken{
+			Link:  sitemap.url,
+			Code:  StatusInvalidSitemap,
+			Error: `empty sitemap`,
+		})
+		return
+	}
+	switch {
+	case nentry == 0:
+		checker.report(sitemap.url, Broken{
+			Link: sitemap.url,
+			Code: StatusInvalidSitemap,
+			Error: fmt.Sprintf(`%q does not have any entry`,
+				root.Name.Local),
+		})
+	case nentry > SitemapMaxUrl:
+		checker.report(sitemap.url, Broken{
+			Link: sitemap.url,
+			Code: StatusInvalidSitemap,
+			Error: fmt.Sprintf(`%q has %d entries, more than %d`,
+				root.Name.Local, nentry, SitemapMaxUrl),
+		})
+	}
+}
+
+// sitemapStatusError the error when the sitemap response status is not
+// 200.
+type sitemapStatusError struct {
+	msg  string
+	code int
+}
+
+func (err *sitemapStatusError) Error() string {
+	return err.msg
+}
+
+// readSitemap fetch the sitemap and return its content, uncompressed if
+// its compressed with gzip.
+// The content is read up to [sitemapMaxSize] plus one byte, to detect
+// the sitemap that is larger than the limit.
+func (checker *sitemapChecker) readSitemap(sitemap sitemapFile) (
+	content []byte, err error,
+) {
+	var httpResp *http.Response
+	httpResp, err = checker.fetch(checker.httpc, sitemap.url)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	switch {
+	case httpResp.StatusCode == http.StatusOK:
+	case httpResp.StatusCode >= http.StatusMultipleChoices &&
+		httpResp.StatusCode < http.StatusBadRequest:
+		return nil, &sitemapStatusError{
+			code: httpResp.StatusCode,
+			msg: `sitemap redirected to ` +
+				httpResp.Header.Get(`Location`),
+		}
+	case httpResp.StatusCode == http.StatusNotFound ||
+		httpResp.StatusCode == http.StatusGone:
+		return nil, &sitemapStatusError{
+			code: httpResp.StatusCode,
+			msg:  `sitemap not found`,
+		}
+	default:
+		return nil, &sitemapStatusError{
+			code: httpResp.StatusCode,
+			msg:  `unexpected status of sitemap`,
+		}
+	}
+
+	content, err = io.ReadAll(io.LimitReader(httpResp.Body,
+		sitemapMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(content, []byte{0x1f, 0x8b}) {
+		return content, nil
+	}
+
+	var gzReader *gzip.Reader
+	gzReader, err = gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	content, err = io.ReadAll(io.LimitReader(gzReader, sitemapMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// checkRoot check the root element of sitemap.
+// It return false if the root element is not valid, so the rest of
+// sitemap is not checked.
+func (checker *sitemapChecker) checkRoot(
+	sitemap sitemapFile, root xml.StartElement,
+) bool {
+	var msg string
+	switch {
+	case root.Name.Local != `urlset` && root.Name.Local != `sitemapindex`:
+		msg = fmt.Sprintf(`unknown root element %q`, root.Name.Local)
+	case root.Name.Space != sitemapXmlns:
+		msg = fmt.Sprintf(`invalid namespace %q, expecting %q`,
+			root.Name.Space, sitemapXmlns)
+	case root.Name.Local == `sitemapindex` && sitemap.inIndex:
+		msg = `sitemap index cannot contains other sitemap index`
+	}
+	if msg == `` {
+		return true
+	}
+	checker.report(sitemap.url, Broken{
+		Link:  sitemap.url,
+		Code:  StatusInvalidSitemap,
+		Error: msg,
+	})
+	return false
+}
+
+// checkEntry check the URL and its optional elements in the sitemap.
+// The URL in the "urlset" is stored for checking later, while the URL in
+// "sitemapindex" is queued as sitemap.
+func (checker *sitemapChecker) checkEntry(
+	sitemap sitemapFile, rootName string, entry sitemapEntry, line int,
+) {
+	var invalid = func(link, msg string) {
+		checker.report(sitemap.url, Broken{
+			Link:  link,
+			Code:  StatusInvalidSitemap,
+			Error: msg,
+			Line:  line,
+		})
+	}
+
+	var loc = strings.TrimSpace(entry.Loc)
+	if loc == `` {
+		invalid(sitemap.url, `missing element "loc"`)
+		return
+	}
+	if entry.LastMod != `` && !isW3cDatetime(entry.LastMod) {
+		invalid(loc, fmt.Sprintf(`invalid lastmod %q`, entry.LastMod))
+	}
+	if entry.ChangeFreq != `` &&
+		!slices.Contains(sitemapChangeFreq, entry.ChangeFreq) {
+		invalid(loc, fmt.Sprintf(`invalid changefreq %q`,
+			entry.ChangeFreq))
+	}
+	if entry.Priority != `` {
+		var priority, err = strconv.ParseFloat(entry.Priority, 64)
+		if err != nil || priority < 0 || priority > 1 {
+			invalid(loc, fmt.Sprintf(`invalid priority %q`,
+				entry.Priority))
+		}
+	}
+
+	if len(loc) > sitemapMaxLocLength {
+		invalid(loc, fmt.Sprintf(`URL is longer than %d characters`,
+			sitemapMaxLocLength))
+		return
+	}
+	var locUrl, err = url.Parse(loc)
+	if err != nil || !locUrl.IsAbs() {
+		invalid(loc, `URL is not absolute`)
+		return
+	}
+	if locUrl.Scheme != checker.siteUrl.Scheme ||
+		locUrl.Host != checker.siteUrl.Host {
+		invalid(loc, `URL is not on the host `+checker.siteUrl.String())
+		return
+	}
+	if !sitemap.fromRobots {
+		// The sitemap can only contains URLs inside its directory,
+		// unless its submitted through robots.txt.
+		var sitemapUrl, _ = url.Parse(sitemap.url)
+		var dir = path.Dir(sitemapUrl.Path)
+		if !strings.HasSuffix(dir, `/`) {
+			dir += `/`
+		}
+		if !strings.HasPrefix(locUrl.Path, dir) {
+			invalid(loc, `URL is outside the directory of sitemap`)
+			return
+		}
+	}
+
+	if rootName == `sitemapindex` {
+		checker.queueSitemap(sitemapFile{
+			url:        loc,
+			parent:     sitemap.url,
+			line:       line,
+			fromRobots: sitemap.fromRobots,
+			inIndex:    true,
+		})
+		return
+	}
+
+	if checker.seenLoc[loc] {
+		invalid(loc, `duplicate URL`)
+		return
+	}
+	checker.seenLoc[loc] = true
+	checker.listLoc = append(checker.listLoc, sitemapLoc{
+		url:     locUrl,
+		sitemap: sitemap.url,
+		line:    line,
+	})
+}
+
+// isW3cDatetime return true if the value is in one of the W3C Datetime
+// format.
+func isW3cDatetime(val string) bool {
+	var listLayout = []string{
+		`2006`,
+		`2006-01`,
+		`2006-01-02`,
+		`2006-01-02T15:04Z07:00`,
+		time.RFC3339,
+	}
+	for _, layout := range listLayout {
+		var _, err = time.Parse(layout, val)
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLocs check the URLs in the sitemaps concurrently, up to
+// [Options.MaxConcurrent] at the same time.
+func (checker *sitemapChecker) checkLocs() {
+	var (
+		wg   sync.WaitGroup
+		slot = make(chan struct{}, checker.opts.MaxConcurrent)
+	)
+	for _, loc := range checker.listLoc {
+		if checker.ctx.Err() != nil {
+			break
+		}
+		slot <- struct{}{}
+		wg.Add(1)
+		go func() {
+			checker.checkLoc(loc)
+			<-slot
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+// checkLoc check that the URL is allowed by robots.txt, and fetching it
+// return status 200 without robots "noindex".
+func (checker *sitemapChecker) checkLoc(loc sitemapLoc) {
+	var link = loc.url.String()
+	var broken = Broken{
+		Link: link,
+		Line: loc.line,
+	}
+
+	if !checker.robots.isAllowed(loc.url.RequestURI()) {
+		broken.Code = StatusInvalidSitemap
+		broken.Error = `URL is disallowed by robots.txt`
+		checker.report(loc.sitemap, broken)
+		return
+	}
+
+	var httpResp, err = checker.fetch(checker.httpc, link)
+	if err != nil {
+		if checker.ctx.Err() != nil {
+			return
+		}
+		broken.Code = StatusBadLink
+		broken.Error = err.Error()
+		checker.report(loc.sitemap, broken)
+		return
+	}
+	defer httpResp.Body.Close()
+
+	broken.Code = httpResp.StatusCode
+	switch {
+	case httpResp.StatusCode == http.StatusOK:
+	case httpResp.StatusCode >= http.StatusMultipleChoices &&
+		httpResp.StatusCode < http.StatusBadRequest:
+		broken.Error = `URL redirected to ` +
+			httpResp.Header.Get(`Location`)
+		checker.report(loc.sitemap, broken)
+		return
+	default:
+		checker.report(loc.sitemap, broken)
+		return
+	}
+
+	var content []byte
+	var mediaType, _, _ = mime.ParseMediaType(
+		httpResp.Header.Get(`Content-Type`))
+	if mediaType == `text/html` || mediaType == `application/xhtml+xml` {
+		content, err = io.ReadAll(httpResp.Body)
+		if err != nil {
+			checker.log.Warn(`checkLoc`, `url`, link,
+				`error`, err.Error())
+		}
+	}
+	var meta = newPageMeta(content, httpResp.Header)
+	if meta.noIndex {
+		broken.Error = `URL has robots "noindex"`
+		checker.report(loc.sitemap, broken)
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestCheckSitemap(t *testing.T) {
+	var srv = httptest.NewServer(nil)
+	defer srv.Close()
+
+	var (
+		robotsTxt = `User-agent: *
+Disallow: /private/
+Allow: /private/public.html
+
+Sitemap: {{.URL}}/sitemap-index.xml
+Sitemap: /relative.xml
+`
+		sitemapIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>{{.URL}}/sitemap-pages.xml</loc>
+    <lastmod>2026-01-02</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>{{.URL}}/missing.xml</loc>
+  </sitemap>
+  <sitemap>
+    <loc>https://other.tld/sitemap.xml</loc>
+  </sitemap>
+</sitemapindex>
+`
+		sitemapPages = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>{{.URL}}/</loc>
+    <lastmod>2026-01-02T03:04:05+07:00</lastmod>
+    <changefreq>weekly</changefreq>
+    <priority>1.0</priority>
+  </url>
+  <url>
+    <loc>{{.URL}}/redirect</loc>
+  </url>
+  <url>
+    <loc>{{.URL}}/noindex</loc>
+  </url>
+  <url>
+    <loc>{{.URL}}/header-noindex</loc>
+  </url>
+  <url>
+    <loc>{{.URL}}/private/secret.html</loc>
+  </url>
+  <url>
+    <loc>{{.URL}}/private/public.html</loc>
+    <lastmod>2026/01/02</lastmod>
+    <changefreq>sometimes</changefreq>
+    <priority>1.5</priority>
+  </url>
+  <url>
+    <loc>{{.URL}}/gone</loc>
+  </url>
+  <url>
+    <loc>https://other.tld/page</loc>
+  </url>
+  <url>
+    <loc>{{.URL}}/</loc>
+  </url>
+</urlset>
+`
+	)
+
+	var replaceUrl = func(content string) string {
+		return strings.ReplaceAll(content, `{{.URL}}`, srv.URL)
+	}
+
+	var mux = http.NewServeMux()
+	mux.HandleFunc(`/robots.txt`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(replaceUrl(robotsTxt)))
+	})
+	mux.HandleFunc(`/sitemap-index.xml`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(`Content-Type`, `application/xml`)
+		w.Write([]byte(replaceUrl(sitemapIndex)))
+	})
+	mux.HandleFunc(`/sitemap-pages.xml`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(`Content-Type`, `application/xml`)
+		w.Write([]byte(replaceUrl(sitemapPages)))
+	})
+	mux.HandleFunc(`/{$}`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`<html><body>Home</body></html>`))
+	})
+	mux.HandleFunc(`/redirect`, func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, `/`, http.StatusMovedPermanently)
+	})
+	mux.HandleFunc(`/noindex`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`<html><head>` +
+			`<meta name="robots" content="noindex">` +
+			`</head><body>No index</body></html>`))
+	})
+	mux.HandleFunc(`/header-noindex`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(`X-Robots-Tag`, `googlebot: noindex`)
+		w.Write([]byte(`<html><body>No index</body></html>`))
+	})
+	mux.HandleFunc(`/private/`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`<html><body>Private</body></html>`))
+	})
+	srv.Config.Handler = mux
+
+	var got, err = brokenlinks.CheckSitemap(context.Background(),
+		brokenlinks.Options{
+			Url: srv.URL,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		robotsUrl  = srv.URL + `/robots.txt`
+		indexUrl   = srv.URL + `/sitemap-index.xml`
+		sitemapUrl = srv.URL + `/sitemap-pages.xml`
+		notOnHost  = `URL is not on the host ` + srv.URL
+	)
+	var exp = map[string][]brokenlinks.Broken{
+		robotsUrl: {{
+			Link:  `/relative.xml`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `sitemap URL is not absolute`,
+			Line:  6,
+		}},
+		indexUrl: {{
+			Link:  srv.URL + `/missing.xml`,
+			Code:  http.StatusNotFound,
+			Error: `sitemap not found`,
+			Line:  7,
+		}, {
+			Link:  `https://other.tld/sitemap.xml`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: notOnHost,
+			Line:  10,
+		}},
+		sitemapUrl: {{
+			Link:  srv.URL + `/`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `duplicate URL`,
+			Line:  33,
+		}, {
+			Link: srv.URL + `/gone`,
+			Code: http.StatusNotFound,
+			Line: 27,
+		}, {
+			Link:  srv.URL + `/header-noindex`,
+			Code:  http.StatusOK,
+			Error: `URL has robots "noindex"`,
+			Line:  15,
+		}, {
+			Link:  srv.URL + `/noindex`,
+			Code:  http.StatusOK,
+			Error: `URL has robots "noindex"`,
+			Line:  12,
+		}, {
+			Link:  srv.URL + `/private/public.html`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `invalid lastmod "2026/01/02"`,
+			Line:  21,
+		}, {
+			Link:  srv.URL + `/private/public.html`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `invalid changefreq "sometimes"`,
+			Line:  21,
+		}, {
+			Link:  srv.URL + `/private/public.html`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `invalid priority "1.5"`,
+			Line:  21,
+		}, {
+			Link:  srv.URL + `/private/secret.html`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: `URL is disallowed by robots.txt`,
+			Line:  18,
+		}, {
+			Link:  srv.URL + `/redirect`,
+			Code:  http.StatusMovedPermanently,
+			Error: `URL redirected to /`,
+			Line:  9,
+		}, {
+			Link:  `https://other.tld/page`,
+			Code:  brokenlinks.StatusInvalidSitemap,
+			Error: notOnHost,
+			Line:  30,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, exp, got.BrokenLinks)
+}
+
+func TestCheckSitemap_robotsRedirect(t *testing.T) {
+	var mux = http.NewServeMux()
+	var srv = httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc(`/robots.txt`, func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, `/robots/1.txt`, http.StatusMovedPermanently)
+	})
+	mux.HandleFunc(`/robots/1.txt`, func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, `/robots/2.txt`, http.StatusFound)
+	})
+	mux.HandleFunc(`/robots/2.txt`, func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`Sitemap: ` + srv.URL + `/sitemap.xml`))
+	})
+	mux.HandleFunc(`/sitemap.xml`, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	var got, err = brokenlinks.CheckSitemap(context.Background(),
+		brokenlinks.Options{
+			Url: srv.URL,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Broken{
+		srv.URL + `/robots.txt`: {{
+			Link:  srv.URL + `/sitemap.xml`,
+			Code:  http.StatusServiceUnavailable,
+			Error: `unexpected status of sitemap`,
+			Line:  1,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, exp, got.BrokenLinks)
+}
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink"
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_sitemap(t *testing.T) {
+	// The files is copied into temporary directory, so changing their
+	// modification time does not modify the test data.
+	var dir = t.TempDir()
+	var err = os.CopyFS(dir, os.DirFS(`testdata/sitemap`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set the modification time of files, which is used as
+	// Last-Modified by local server.
+	var mtime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var listFile = []string{
+		`about.html`,
+		`copy.html`,
+		`docs/index.html`,
+		`draft.html`,
+		`index.html`,
+		`logo.png`,
+		`notes.txt`,
+	}
+	for _, name := range listFile {
+		err = os.Chtimes(filepath.Join(dir, name), mtime, mtime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got *brokenlinks.Result
+	got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     dir,
+		NoCache: true,
+		Sitemap: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lastMod = `2026-01-02T03:04:05Z`
+	var exp = brokenlinks.Sitemap{
+		{Loc: `/`, LastMod: lastMod},
+		{Loc: `/about.html`, LastMod: lastMod},
+		{Loc: `/docs/`, LastMod: lastMod},
+	}
+	test.Assert(t, `Sitemap`, exp, got.Sitemap)
+
+	var outDir = t.TempDir()
+	var files []string
+	files, err = got.Sitemap.Write(outDir, `https://web.tld`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `files`,
+		[]string{filepath.Join(outDir, `sitemap.xml`)}, files)
+
+	var content []byte
+	content, err = os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expXml = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://web.tld/</loc>
+    <lastmod>2026-01-02T03:04:05Z</lastmod>
+  </url>
+  <url>
+    <loc>https://web.tld/about.html</loc>
+    <lastmod>2026-01-02T03:04:05Z</lastmod>
+  </url>
+  <url>
+    <loc>https://web.tld/docs/</loc>
+    <lastmod>2026-01-02T03:04:05Z</lastmod>
+  </url>
+</urlset>
+`
+	test.Assert(t, `sitemap.xml`, expXml, string(content))
+}
+
+func TestScan_sitemapRedirect(t *testing.T) {
+	var listPage = map[string]string{
+		`/`: `<html><body>` +
+			`<a href="/old">Old</a>` +
+			`<a href="/new">New</a>` +
+			`<a href="/dir">Dir</a>` +
+			`<a href="/moved">Moved</a>` +
+			`</body></html>`,
+		`/new`:   `<html><body>New</body></html>`,
+		`/dir/`:  `<html><body>Dir</body></html>`,
+		`/moved`: `<html><head><link rel="canonical" href="/old"></head></html>`,
+	}
+	var mux = http.NewServeMux()
+	mux.Handle(`/old`, http.RedirectHandler(`/new`, http.StatusMovedPermanently))
+	mux.Handle(`/dir`, http.RedirectHandler(`/dir/`, http.StatusMovedPermanently))
+	mux.HandleFunc(`/`, func(resp http.ResponseWriter, req *http.Request) {
+		var body, ok = listPage[req.URL.Path]
+		if !ok {
+			http.NotFound(resp, req)
+			return
+		}
+		resp.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+		_, _ = resp.Write([]byte(body))
+	})
+	var srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     srv.URL,
+		NoCache: true,
+		Sitemap: true,
+		Seo:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The page "/old" redirected to other page so it is not included,
+	// while "/dir" redirected to the same path with trailing slash so
+	// it is recorded using the last URL.
+	var exp = brokenlinks.Sitemap{
+		{Loc: srv.URL},
+		{Loc: srv.URL + `/dir/`},
+		{Loc: srv.URL + `/new`},
+	}
+	test.Assert(t, `Sitemap`, exp, got.Sitemap)
+
+	var listIssue []brokenlinks.Issue
+	for _, issue := range got.Issues[srv.URL+`/moved`] {
+		if issue.Rule == brokenlinks.SeoCanonicalStatus {
+			listIssue = append(listIssue, issue)
+		}
+	}
+	var expIssue = []brokenlinks.Issue{{
+		Rule:    brokenlinks.SeoCanonicalStatus,
+		Message: `canonical link return status 301`,
+		Link:    srv.URL + `/old`,
+	}}
+	test.Assert(t, `Issues /moved`, expIssue, listIssue)
+}
+
+// TestScan_sitemapCache test that the robots "noindex" and canonical link
+// of page, stored in the cache by scan without Sitemap, are used when the
+// content of page does not change.
+func TestScan_sitemapCache(t *testing.T) {
+	var listPage = map[string]string{
+		`/`: `<html><body>` +
+			`<a href="/draft">Draft</a>` +
+			`<a href="/copy">Copy</a>` +
+			`<a href="/new">New</a>` +
+			`</body></html>`,
+		`/draft`: `<html><head><meta name="robots" content="noindex"></head></html>`,
+		`/copy`:  `<html><head><link rel="canonical" href="/new"></head></html>`,
+		`/new`:   `<html><body>New</body></html>`,
+	}
+	// The server does not support conditional request, so the page
+	// is always returned with status 200.
+	var srv = httptest.NewServer(http.HandlerFunc(
+		func(resp http.ResponseWriter, req *http.Request) {
+			var body, ok = listPage[req.URL.Path]
+			if !ok {
+				http.NotFound(resp, req)
+				return
+			}
+			resp.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+			resp.Header().Set(`ETag`, `"`+req.URL.Path+`"`)
+			_, _ = resp.Write([]byte(body))
+		}))
+	t.Cleanup(srv.Close)
+
+	var cache = jarink.NewMemoryCache()
+	var _, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:   srv.URL,
+		Cache: cache,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *brokenlinks.Result
+	got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     srv.URL,
+		Cache:   cache,
+		Sitemap: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = brokenlinks.Sitemap{
+		{Loc: srv.URL},
+		{Loc: srv.URL + `/new`},
+	}
+	test.Assert(t, `Sitemap`, exp, got.Sitemap)
+}
+
+func TestSitemap_Write_index(t *testing.T) {
+	var sitemap = make(brokenlinks.Sitemap, 0, brokenlinks.SitemapMaxUrl+1)
+	for x := range brokenlinks.SitemapMaxUrl + 1 {
+		sitemap = append(sitemap, brokenlinks.SitemapUrl{
+			Loc: fmt.Sprintf(`/page/%d`, x),
+		})
+	}
+
+	var outDir = t.TempDir()
+	var files, err = sitemap.Write(outDir, `https://web.tld/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expFiles = []string{
+		filepath.Join(outDir, `sitemap.xml`),
+		filepath.Join(outDir, `sitemap-1.xml`),
+		filepath.Join(outDir, `sitemap-2.xml`),
+	}
+	test.Assert(t, `files`, expFiles, files)
+
+	var content []byte
+	content, err = os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expIndex = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://web.tld/sitemap-1.xml</loc>
+  </sitemap>
+  <sitemap>
+    <loc>https://web.tld/sitemap-2.xml</loc>
+  </sitemap>
+</sitemapindex>
+`
+	test.Assert(t, `sitemap.xml`, expIndex, string(content))
+
+	content, err = os.ReadFile(files[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expLast = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://web.tld/page/50000</loc>
+  </url>
+</urlset>
+`
+	test.Assert(t, `sitemap-2.xml`, expLast, string(content))
+
+	_, err = sitemap.Write(outDir, ``)
+	test.Assert(t, `Write without base URL`,
+		`Write: "/page/0": missing base URL`, err.Error())
+}
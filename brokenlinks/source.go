@@ -0,0 +1,396 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/atom"
+)
+
+// List of source file formats.
+const (
+	sourceMarkdown = iota + 1
+	sourceAsciidoc
+)
+
+// schemeRx match the URL scheme in the link, for example "https:" or
+// "mailto:".
+var schemeRx = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// sourceLink contains the link found inside the source file.
+type sourceLink struct {
+	// value of the link, as written in the source.
+	value string
+
+	// text of the link or alternate text of image.
+	text string
+
+	// line and column of the link in the source, start from 1.
+	line   int
+	column int
+
+	kind atom.Atom
+
+	// isXref true if the link is AsciiDoc cross reference, where the
+	// value without "#" and file extension is the ID in the same file.
+	isXref bool
+}
+
+// sourceFile contains the links and anchors inside the Markdown or
+// AsciiDoc file.
+type sourceFile struct {
+	// anchors contains the ID of sections and anchors in the file.
+	anchors map[string]bool
+
+	path  string
+	links []sourceLink
+
+	format int
+}
+
+// sourceFormatOf return the format of source file based on its extension,
+// or 0 if the file is not Markdown or AsciiDoc.
+func sourceFormatOf(path string) int {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case `.md`, `.markdown`:
+		return sourceMarkdown
+	case `.adoc`, `.asciidoc`:
+		return sourceAsciidoc
+	}
+	return 0
+}
+
+// parseSourceFile read the file and extract its links and anchors.
+// The file without Markdown extension is parsed as AsciiDoc.
+func parseSourceFile(path string) (file *sourceFile, err error) {
+	var content []byte
+	content, err = os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file = &sourceFile{
+		path:   path,
+		format: sourceFormatOf(path),
+	}
+	if file.format == sourceMarkdown {
+		file.links, file.anchors = extractMarkdown(content)
+	} else {
+		file.format = sourceAsciidoc
+		file.links, file.anchors = extractAsciidoc(content)
+	}
+	return file, nil
+}
+
+// sourceDir contains the source files inside directory.
+type sourceDir struct {
+	// files contains the parsed source file by its path.
+	files map[string]*sourceFile
+
+	// root the directory for resolving link with absolute path.
+	root string
+
+	// listPath contains the path of source files to be scanned,
+	// sorted.
+	listPath []string
+}
+
+// loadSourceDir walk the directory root and parse all of the Markdown and
+// AsciiDoc files inside it.
+// If root is a file, only that file is parsed.
+func loadSourceDir(root string) (src *sourceDir, err error) {
+	src = &sourceDir{
+		files: map[string]*sourceFile{},
+		root:  root,
+	}
+
+	var fi os.FileInfo
+	fi, err = os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		src.root = filepath.Dir(root)
+		src.listPath = []string{root}
+	} else {
+		err = filepath.WalkDir(root,
+			func(path string, entry fs.DirEntry, errWalk error) error {
+				if errWalk != nil {
+					return errWalk
+				}
+				if entry.IsDir() {
+					if path != root &&
+						strings.HasPrefix(entry.Name(), `.`) {
+						// Skip hidden directory, like
+						// ".git".
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if sourceFormatOf(path) != 0 {
+					src.listPath = append(src.listPath, path)
+				}
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(src.listPath)
+	for _, path := range src.listPath {
+		_, err = src.file(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+// file return the parsed source file by its path.
+func (src *sourceDir) file(path string) (file *sourceFile, err error) {
+	file = src.files[path]
+	if file != nil {
+		return file, nil
+	}
+	file, err = parseSourceFile(path)
+	if err != nil {
+		return nil, err
+	}
+	src.files[path] = file
+	return file, nil
+}
+
+// resolve the link to local file inside the source file from.
+// It return the path of target file, with anchor if any, and an error if
+// the target file or its anchor does not exist.
+// The error wrap [fs.ErrNotExist] if the target file does not exist.
+func (src *sourceDir) resolve(from *sourceFile, slink sourceLink) (
+	link string, err error,
+) {
+	var targetPath, anchor, hasAnchor = strings.Cut(slink.value, `#`)
+	targetPath, _, _ = strings.Cut(targetPath, `?`)
+	if slink.isXref {
+		switch {
+		case !hasAnchor && sourceFormatOf(targetPath) == 0:
+			// The "<<id>>" or "xref:id[]" refer to the ID in
+			// the same file.
+			anchor = targetPath
+			targetPath = ``
+		case targetPath != `` && filepath.Ext(targetPath) == ``:
+			targetPath += `.adoc`
+		}
+	}
+
+	var target *sourceFile
+	if targetPath == `` {
+		target = from
+		targetPath = from.path
+	} else {
+		var unescaped, errUnescape = url.PathUnescape(targetPath)
+		if errUnescape == nil {
+			targetPath = unescaped
+		}
+		if strings.HasPrefix(targetPath, `/`) {
+			targetPath = filepath.Join(src.root,
+				filepath.FromSlash(targetPath))
+		} else {
+			targetPath = filepath.Join(filepath.Dir(from.path),
+				filepath.FromSlash(targetPath))
+		}
+		targetPath, err = src.stat(targetPath)
+		if err != nil {
+			return joinAnchor(targetPath, anchor), err
+		}
+	}
+
+	link = joinAnchor(targetPath, anchor)
+	if anchor == `` {
+		return link, nil
+	}
+	if target == nil {
+		if sourceFormatOf(targetPath) == 0 {
+			// The anchor in non-source file, for example HTML,
+			// is not checked.
+			return link, nil
+		}
+		target, err = src.file(targetPath)
+		if err != nil {
+			return link, err
+		}
+	}
+	if !target.anchors[anchor] {
+		return link, fmt.Errorf(`anchor %q not found`, anchor)
+	}
+	return link, nil
+}
+
+// stat check if the file exist.
+// If the file with extension ".html" does not exist, it will try the
+// source file with the same name, since the source file is rendered into
+// HTML.
+func (src *sourceDir) stat(path string) (string, error) {
+	var _, err = os.Stat(path)
+	if err == nil {
+		return path, nil
+	}
+	var ext = filepath.Ext(path)
+	if ext != `.html` && ext != `.htm` {
+		return path, err
+	}
+	var base = strings.TrimSuffix(path, ext)
+	for _, srcExt := range []string{`.adoc`, `.asciidoc`, `.md`} {
+		_, errSrc := os.Stat(base + srcExt)
+		if errSrc == nil {
+			return base + srcExt, nil
+		}
+	}
+	return path, err
+}
+
+func joinAnchor(path, anchor string) string {
+	if anchor == `` {
+		return path
+	}
+	return path + `#` + anchor
+}
+
+// scanSource scan the links inside the Markdown and AsciiDoc files in
+// the directory [Options.Url].
+// The links to local files and anchors are checked directly, while the
+// external links are fetched like in scanAll.
+func (wrk *worker) scanSource() (result *Result, err error) {
+	var src *sourceDir
+	src, err = loadSourceDir(wrk.opts.localDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range src.listPath {
+		var (
+			file = src.files[path]
+
+			// parentUrl use Opaque so its String return the
+			// path as is.
+			parentUrl = &url.URL{Opaque: path}
+			resultq   = map[string]linkQueue{}
+		)
+		for _, slink := range file.links {
+			var scheme = strings.ToLower(schemeRx.FindString(slink.value))
+			switch scheme {
+			case ``:
+				wrk.checkSourceLink(src, file, parentUrl, slink,
+					resultq)
+				continue
+			case `http:`, `https:`:
+			default:
+				// Other schemes, like "mailto:", are not
+				// checked.
+				continue
+			}
+
+			var linkq = wrk.processLink(parentUrl, slink.value, slink.kind)
+			if linkq == nil {
+				continue
+			}
+			var prevLink, seen = resultq[linkq.url]
+			if seen {
+				prevLink.count++
+				resultq[linkq.url] = prevLink
+				continue
+			}
+			linkq.text = slink.text
+			linkq.line = slink.line
+			linkq.column = slink.column
+			linkq.count = 1
+			linkq.isExternal = true
+			resultq[linkq.url] = *linkq
+		}
+
+		// The local link that broken has status.
+		var listLocal []linkQueue
+		for url, linkq := range resultq {
+			if linkq.status != 0 {
+				listLocal = append(listLocal, linkq)
+				delete(resultq, url)
+			}
+		}
+		for _, linkq := range listLocal {
+			wrk.markBroken(linkq)
+		}
+		wrk.processResult(resultq)
+	}
+
+	return wrk.processAndWait()
+}
+
+// checkSourceLink check the link to local file in source file.
+// If the link is broken, it stored in resultq with status 404.
+func (wrk *worker) checkSourceLink(
+	src *sourceDir, file *sourceFile, parentUrl *url.URL,
+	slink sourceLink, resultq map[string]linkQueue,
+) {
+	var link, err = src.resolve(file, slink)
+	if err == nil {
+		return
+	}
+	var prevLink, seen = resultq[link]
+	if seen {
+		prevLink.count++
+		resultq[link] = prevLink
+		return
+	}
+	var linkq = linkQueue{
+		parentUrl: parentUrl,
+		url:       link,
+		text:      slink.text,
+		kind:      slink.kind,
+		status:    http.StatusNotFound,
+		line:      slink.line,
+		column:    slink.column,
+		count:     1,
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		linkq.errScan = err
+	}
+	resultq[link] = linkq
+}
+
+// columnOf return the column, in runes, of byte offset in line.
+func columnOf(line string, offset int) int {
+	return utf8.RuneCountInString(line[:offset]) + 1
+}
+
+// normalizeText remove the leading, trailing, and repeated spaces in
+// text.
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), ` `)
+}
+
+// span the start and end byte offset in line.
+type span struct {
+	start int
+	end   int
+}
+
+// inSpan return true if the offset is inside one of the spans.
+func inSpan(listSpan []span, offset int) bool {
+	for _, sp := range listSpan {
+		if offset >= sp.start && offset < sp.end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	adocSectionRx     = regexp.MustCompile(`^(={1,6}|#{1,6})[ \t]+(.+?)[ \t]*$`)
+	adocAttrRx        = regexp.MustCompile(`^:([\w-]+):(?:[ \t]+(.*))?$`)
+	adocBlockAnchorRx = regexp.MustCompile(`^\[\[([^\],\s]+)(?:,[^\]]*)?\]\]$|^\[#([^\].,%\s]+)[^\]]*\]$`)
+	adocAnchorRx      = regexp.MustCompile(`\[\[([^\],\s]+)(?:,[^\]]*)?\]\]|anchor:([^\s\[]+)\[|\[#([^\].,%\s]+)[^\]]*\]#`)
+	adocDelimiterRx   = regexp.MustCompile("^(?:-{4,}|\\.{4,}|/{4,}|\\+{4,}|```)$")
+	adocXrefRx        = regexp.MustCompile(`<<([^,>]+)(?:,([^>]*))?>>`)
+	adocMacroRx       = regexp.MustCompile(`\b(link|image|xref):(:?)([^\s\[]+)\[([^\]]*)\]`)
+	adocUrlRx         = regexp.MustCompile(`\b((?:https?|ftp)://[^\s\[\]<>"]+)(?:\[([^\]]*)\])?`)
+	adocAttrRefRx     = regexp.MustCompile(`\{([\w-]+)\}(?:\[([^\]]*)\])?`)
+)
+
+// extractAsciidoc return the links and anchors in the AsciiDoc content.
+// The links inside the comments, listing, literal, and passthrough blocks
+// are ignored.
+// The anchors are the section IDs generated using the same rules as
+// Asciidoctor, the section titles, and the explicit anchors like "[[id]]",
+// "[#id]", and "anchor:id[]".
+func extractAsciidoc(content []byte) (
+	listLink []sourceLink, anchors map[string]bool,
+) {
+	anchors = map[string]bool{}
+
+	var (
+		lines = bytes.Split(content, []byte("\n"))
+		attrs = map[string]string{
+			`idprefix`:    `_`,
+			`idseparator`: `_`,
+		}
+		delimiter  string
+		hasBlockID bool
+	)
+	for x, raw := range lines {
+		var line = strings.TrimRight(string(raw), " \t\r")
+
+		if delimiter != `` {
+			if line == delimiter {
+				delimiter = ``
+			}
+			continue
+		}
+		if adocDelimiterRx.MatchString(line) {
+			delimiter = line
+			hasBlockID = false
+			continue
+		}
+		if strings.HasPrefix(line, `//`) {
+			continue
+		}
+
+		var m = adocAttrRx.FindStringSubmatch(line)
+		if m != nil {
+			attrs[m[1]] = m[2]
+			continue
+		}
+
+		m = adocBlockAnchorRx.FindStringSubmatch(line)
+		if m != nil {
+			anchors[m[1]+m[2]] = true
+			hasBlockID = true
+			continue
+		}
+		if strings.HasPrefix(line, `[`) && strings.HasSuffix(line, `]`) {
+			// Other block attributes, like "[source,go]", does
+			// not reset the explicit ID.
+			continue
+		}
+
+		m = adocSectionRx.FindStringSubmatch(line)
+		if m != nil {
+			var title = m[2]
+			anchors[title] = true
+			// The document title, level 0, does not have ID.
+			if len(m[1]) > 1 && !hasBlockID {
+				var id = asciidocSectionID(title, attrs[`idprefix`],
+					attrs[`idseparator`])
+				var base = id
+				for n := 2; anchors[id]; n++ {
+					id = base + attrs[`idseparator`] + strconv.Itoa(n)
+				}
+				anchors[id] = true
+			}
+		}
+		if line != `` {
+			hasBlockID = false
+		}
+
+		for _, m := range adocAnchorRx.FindAllStringSubmatch(line, -1) {
+			anchors[m[1]+m[2]+m[3]] = true
+		}
+		listLink = append(listLink, asciidocLinks(line, x+1, attrs)...)
+	}
+	return listLink, anchors
+}
+
+// asciidocLinks return the links in a line of AsciiDoc.
+// The attribute reference in the link target, for example
+// "link:{url-home}[]", is replaced with the value of attribute in attrs.
+func asciidocLinks(line string, lineNum int, attrs map[string]string) (
+	listLink []sourceLink,
+) {
+	var listSpan []span
+	var add = func(start, end int, value, text string, kind atom.Atom, isXref bool) {
+		listSpan = append(listSpan, span{start: start, end: end})
+		value = adocAttrRefRx.ReplaceAllStringFunc(value, func(ref string) string {
+			var v, ok = attrs[strings.Trim(ref, `{}`)]
+			if !ok {
+				return ref
+			}
+			return v
+		})
+		listLink = append(listLink, sourceLink{
+			value:  value,
+			text:   normalizeText(text),
+			line:   lineNum,
+			column: columnOf(line, start),
+			kind:   kind,
+			isXref: isXref,
+		})
+	}
+
+	for _, m := range adocMacroRx.FindAllStringSubmatchIndex(line, -1) {
+		var (
+			name   = line[m[2]:m[3]]
+			target = line[m[6]:m[7]]
+			text   = line[m[8]:m[9]]
+		)
+		switch name {
+		case `image`:
+			text, _, _ = strings.Cut(text, `,`)
+			add(m[0], m[1], target, text, atom.Img, false)
+		case `xref`:
+			add(m[0], m[1], target, text, atom.A, true)
+		default:
+			add(m[0], m[1], target, text, atom.A, false)
+		}
+	}
+	for _, m := range adocXrefRx.FindAllStringSubmatchIndex(line, -1) {
+		var text string
+		if m[4] >= 0 {
+			text = line[m[4]:m[5]]
+		}
+		add(m[0], m[1], strings.TrimSpace(line[m[2]:m[3]]), text,
+			atom.A, true)
+	}
+	for _, m := range adocUrlRx.FindAllStringSubmatchIndex(line, -1) {
+		if inSpan(listSpan, m[0]) {
+			continue
+		}
+		var text string
+		if m[4] >= 0 {
+			text = line[m[4]:m[5]]
+		}
+		var value = line[m[2]:m[3]]
+		if m[4] < 0 {
+			value = strings.TrimRight(value, `.,;:!?)`)
+		}
+		add(m[0], m[0]+len(value), value, text, atom.A, false)
+	}
+	for _, m := range adocAttrRefRx.FindAllStringSubmatchIndex(line, -1) {
+		if inSpan(listSpan, m[0]) {
+			continue
+		}
+		var value = attrs[line[m[2]:m[3]]]
+		if schemeRx.FindString(value) == `` {
+			// Only the attribute that contains URL is a link.
+			continue
+		}
+		var text string
+		if m[4] >= 0 {
+			text = line[m[4]:m[5]]
+		}
+		add(m[0], m[1], value, text, atom.A, false)
+	}
+	return listLink
+}
+
+// asciidocSectionID generate the section ID from its title using the same
+// rules as Asciidoctor: the title is converted to lower case, the
+// characters other than word, space, hyphen, and period are removed, and
+// the sequences of space, hyphen, and period are replaced with separator.
+func asciidocSectionID(title, prefix, separator string) string {
+	title = strings.ToLower(title)
+
+	var (
+		sb     strings.Builder
+		isPrev bool
+	)
+	for _, r := range title {
+		switch {
+		case r == ' ' || r == '-' || r == '.':
+			if separator == `` {
+				continue
+			}
+			if !isPrev {
+				sb.WriteString(separator)
+			}
+			isPrev = true
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) ||
+			unicode.IsMark(r):
+			sb.WriteRune(r)
+			isPrev = false
+		}
+	}
+	var id = strings.TrimSuffix(sb.String(), separator)
+	if prefix == `` {
+		id = strings.TrimPrefix(id, separator)
+	}
+	return prefix + id
+}
@@ -0,0 +1,366 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	mdHeadingRx    = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	mdHeadingIDRx  = regexp.MustCompile(`[ \t]*\{#([^}\s]+)\}$`)
+	mdSetextRx     = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	mdRefDefRx     = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?`)
+	mdAutolinkRx   = regexp.MustCompile(`<((?:https?|ftp)://[^\s>]+)>`)
+	mdHTMLLinkRx   = regexp.MustCompile(`<(a|img)\b[^>]*?\b(?:href|src)="([^"]*)"`)
+	mdHTMLAnchorRx = regexp.MustCompile(`<a\b[^>]*?\b(?:name|id)="([^"]+)"`)
+	mdInlineLinkRx = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	mdListItemRx   = regexp.MustCompile(`^ {0,3}(?:[-+*]|[0-9]{1,9}[.)])(?:[ \t]|$)`)
+	bareUrlRx      = regexp.MustCompile("(?:https?|ftp)://[^\\s<>\\[\\]()\"'`]+")
+)
+
+// extractMarkdown return the links and anchors in the Markdown content.
+// The links inside the fenced code blocks, indented code blocks, code
+// spans, and HTML comments are ignored.
+// The anchors are the heading IDs generated using the same rules as
+// GitHub, the custom heading ID "{#id}", and the HTML anchor
+// `<a name="id">`.
+func extractMarkdown(content []byte) (
+	listLink []sourceLink, anchors map[string]bool,
+) {
+	anchors = map[string]bool{}
+
+	var (
+		lines    = bytes.Split(content, []byte("\n"))
+		slugs    = map[string]int{}
+		fence    string
+		prevLine string
+		indent   markdownIndent
+
+		// inComment true if the line is inside the HTML comment
+		// that started on previous line.
+		inComment bool
+	)
+	var addHeading = func(text string) {
+		var m = mdHeadingIDRx.FindStringSubmatch(text)
+		if m != nil {
+			anchors[m[1]] = true
+			return
+		}
+		var id = markdownSlug(text)
+		var n = slugs[id]
+		slugs[id] = n + 1
+		if n > 0 {
+			id += `-` + strconv.Itoa(n)
+		}
+		anchors[id] = true
+	}
+
+	for x, raw := range lines {
+		var line = strings.TrimRight(string(raw), "\r")
+		var trimmed = strings.TrimSpace(line)
+
+		if fence != `` {
+			if strings.HasPrefix(trimmed, fence) &&
+				strings.Trim(trimmed, fence[:1]) == `` {
+				fence = ``
+			}
+			prevLine = ``
+			indent.blank()
+			continue
+		}
+
+		// The masked line has the code spans and HTML comments
+		// replaced with spaces.
+		var masked string
+		masked, inComment = maskHtmlComments(maskCodeSpans(line), inComment)
+		if strings.TrimSpace(masked) == `` {
+			prevLine = ``
+			indent.blank()
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") ||
+			strings.HasPrefix(trimmed, `~~~`) {
+			var n = len(trimmed) - len(strings.TrimLeft(trimmed, trimmed[:1]))
+			fence = trimmed[:n]
+			prevLine = ``
+			indent.blank()
+			continue
+		}
+		if indent.isCode(line) {
+			continue
+		}
+
+		var m = mdHeadingRx.FindStringSubmatch(line)
+		if m != nil {
+			addHeading(m[2])
+		} else if prevLine != `` && mdSetextRx.MatchString(line) {
+			addHeading(strings.TrimSpace(prevLine))
+		}
+		prevLine = trimmed
+
+		listLink = append(listLink, markdownLinks(masked, x+1)...)
+
+		for _, m := range mdHTMLAnchorRx.FindAllStringSubmatch(masked, -1) {
+			anchors[m[1]] = true
+		}
+	}
+	return listLink, anchors
+}
+
+// markdownLinks return the links in a line of Markdown.
+func markdownLinks(line string, lineNum int) (listLink []sourceLink) {
+	line = maskCodeSpans(line)
+
+	var listSpan []span
+	var add = func(start, end int, value, text string, kind atom.Atom) {
+		listSpan = append(listSpan, span{start: start, end: end})
+		listLink = append(listLink, sourceLink{
+			value:  value,
+			text:   normalizeText(text),
+			line:   lineNum,
+			column: columnOf(line, start),
+			kind:   kind,
+		})
+	}
+
+	var m = mdRefDefRx.FindStringSubmatchIndex(line)
+	if m != nil {
+		add(m[0], m[1], line[m[4]:m[5]], line[m[2]:m[3]], atom.A)
+		return listLink
+	}
+
+	for x := 0; x < len(line); x++ {
+		if line[x] == '\\' {
+			x++
+			continue
+		}
+		if line[x] != '[' {
+			continue
+		}
+		var endText = matchBracket(line, x, '[', ']')
+		if endText < 0 || endText+1 >= len(line) || line[endText+1] != '(' {
+			continue
+		}
+		var endDest = matchBracket(line, endText+1, '(', ')')
+		if endDest < 0 {
+			continue
+		}
+		var dest = markdownDestination(line[endText+2 : endDest])
+		if dest == `` {
+			continue
+		}
+		var start = x
+		var kind = atom.A
+		if x > 0 && line[x-1] == '!' {
+			start--
+			kind = atom.Img
+		}
+		// The link text may contains another link, for example
+		// image, so continue from the start of text.
+		add(start, endDest+1, dest, line[x+1:endText], kind)
+	}
+
+	for _, m := range mdAutolinkRx.FindAllStringSubmatchIndex(line, -1) {
+		add(m[0], m[1], line[m[2]:m[3]], ``, atom.A)
+	}
+	for _, m := range mdHTMLLinkRx.FindAllStringSubmatchIndex(line, -1) {
+		var kind = atom.A
+		if line[m[2]:m[3]] == `img` {
+			kind = atom.Img
+		}
+		add(m[0], m[1], line[m[4]:m[5]], ``, kind)
+	}
+	for _, m := range bareUrlRx.FindAllStringIndex(line, -1) {
+		if inSpan(listSpan, m[0]) {
+			continue
+		}
+		var value = strings.TrimRight(line[m[0]:m[1]], `.,;:!?*_~`)
+		add(m[0], m[0]+len(value), value, ``, atom.A)
+	}
+	return listLink
+}
+
+// markdownDestination return the link destination from the content
+// inside the parentheses, without the title.
+func markdownDestination(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, `<`) {
+		var end = strings.IndexByte(content, '>')
+		if end < 0 {
+			return ``
+		}
+		return content[1:end]
+	}
+	var fields = strings.Fields(content)
+	if len(fields) == 0 {
+		return ``
+	}
+	return fields[0]
+}
+
+// matchBracket return the index of closing bracket that match with the
+// opening bracket at index start, or -1 if not found.
+func matchBracket(line string, start int, open, close byte) int {
+	var depth int
+	for x := start; x < len(line); x++ {
+		switch line[x] {
+		case '\\':
+			x++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return x
+			}
+		}
+	}
+	return -1
+}
+
+// maskCodeSpans replace each character inside the code spans with space,
+// so the links inside it are ignored while the column of other links are
+// not changed.
+func maskCodeSpans(line string) string {
+	if !strings.Contains(line, "`") {
+		return line
+	}
+	var (
+		sb    strings.Builder
+		runes = []rune(line)
+	)
+	for x := 0; x < len(runes); x++ {
+		if runes[x] != '`' {
+			sb.WriteRune(runes[x])
+			continue
+		}
+		var n = 1
+		for x+n < len(runes) && runes[x+n] == '`' {
+			n++
+		}
+		var delim = strings.Repeat("`", n)
+		var end = strings.Index(string(runes[x+n:]), delim)
+		if end < 0 {
+			sb.WriteString(delim)
+			x += n - 1
+			continue
+		}
+		var lenCode = len([]rune(string(runes[x+n:])[:end]))
+		sb.WriteString(strings.Repeat(` `, n+lenCode+n))
+		x += n + lenCode + n - 1
+	}
+	return sb.String()
+}
+
+// markdownIndent track the indented code block and list item on each
+// non-blank line of Markdown.
+type markdownIndent struct {
+	// hasText true if the previous line is not blank.
+	hasText bool
+
+	// inList true if the line is inside the list item, where the
+	// indented line is the content of item instead of code block.
+	inList bool
+
+	// inCode true if the previous line is indented code block.
+	inCode bool
+}
+
+// blank mark the current line as blank line or line of fenced code
+// block.
+func (indent *markdownIndent) blank() {
+	indent.hasText = false
+}
+
+// isCode return true if the non-blank line is inside the indented code
+// block.
+// The indented code block can not interrupt paragraph and the indented
+// line inside the list item is the content of item.
+func (indent *markdownIndent) isCode(line string) bool {
+	var isIndented = isIndentedCode(line)
+	if isIndented && !indent.inList && (!indent.hasText || indent.inCode) {
+		indent.inCode = true
+		return true
+	}
+	indent.inCode = false
+	if mdListItemRx.MatchString(line) {
+		indent.inList = true
+	} else if !isIndented && !indent.hasText {
+		indent.inList = false
+	}
+	indent.hasText = true
+	return false
+}
+
+// isIndentedCode return true if the line is indented with at least four
+// spaces or a tab.
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, `    `) || strings.HasPrefix(line, "\t")
+}
+
+// maskHtmlComments replace each character inside the HTML comments with
+// space, like [maskCodeSpans].
+// The inComment parameter is true if the line is inside the comment that
+// started on previous line.
+// It return true if the comment in the line is not closed.
+func maskHtmlComments(line string, inComment bool) (string, bool) {
+	if !inComment && !strings.Contains(line, `<!--`) {
+		return line, false
+	}
+	var sb strings.Builder
+	for line != `` {
+		if !inComment {
+			var start = strings.Index(line, `<!--`)
+			if start < 0 {
+				sb.WriteString(line)
+				break
+			}
+			sb.WriteString(line[:start])
+			sb.WriteString(`    `)
+			line = line[start+4:]
+			inComment = true
+		}
+		var end = strings.Index(line, `-->`)
+		if end < 0 {
+			sb.WriteString(strings.Repeat(` `, utf8.RuneCountInString(line)))
+			break
+		}
+		sb.WriteString(strings.Repeat(` `, utf8.RuneCountInString(line[:end])+3))
+		line = line[end+3:]
+		inComment = false
+	}
+	return sb.String(), inComment
+}
+
+// markdownSlug generate the heading ID using the same rules as GitHub:
+// the text is converted to lower case, the link is replaced with its text,
+// the punctuation except hyphen and underscore is removed, and the space
+// is replaced with hyphen.
+func markdownSlug(text string) string {
+	text = mdInlineLinkRx.ReplaceAllString(text, `$1`)
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == ' ':
+			sb.WriteByte('-')
+		case r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) ||
+			unicode.IsMark(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
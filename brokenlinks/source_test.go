@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_source(t *testing.T) {
+	var dir = filepath.Join(`testdata`, `source`)
+	var opts = brokenlinks.Options{
+		Url:     dir,
+		NoCache: true,
+		Source:  true,
+	}
+	var got, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Broken{
+		filepath.Join(dir, `README.adoc`): {{
+			Link:    `http://127.0.0.1:11836/notexist`,
+			Text:    `not exist`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    14,
+			Column:  25,
+			Count:   1,
+		}, {
+			Link:    filepath.Join(dir, `README.adoc`) + `#not-exist`,
+			Error:   `anchor "not-exist" not found`,
+			Text:    `missing section`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    9,
+			Column:  5,
+			Count:   1,
+		}, {
+			Link:    filepath.Join(dir, `missing.adoc`),
+			Text:    `missing file`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    9,
+			Column:  39,
+			Count:   1,
+		}},
+		filepath.Join(dir, `guide.md`): {{
+			Link:    `http://127.0.0.1:11836/notexist`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    14,
+			Column:  41,
+			Count:   1,
+		}, {
+			Link:    filepath.Join(dir, `docs`, `missing.png`),
+			Text:    `image`,
+			Element: `img@src`,
+			Code:    http.StatusNotFound,
+			Line:    8,
+			Column:  1,
+			Count:   1,
+		}, {
+			Link:    filepath.Join(dir, `guide.md`) + `#after-comment`,
+			Error:   `anchor "after-comment" not found`,
+			Text:    `after comment`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    16,
+			Column:  47,
+			Count:   1,
+		}, {
+			Link:    filepath.Join(dir, `guide.md`) + `#notexist`,
+			Error:   `anchor "notexist" not found`,
+			Text:    `missing anchor`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    7,
+			Column:  26,
+			Count:   1,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, exp, got.BrokenLinks)
+}
+
+func TestScan_sourceInvalid(t *testing.T) {
+	var _, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:    `http://127.0.0.1:11836`,
+		Source: true,
+	})
+	var gotError string
+	if err != nil {
+		gotError = err.Error()
+	}
+	test.Assert(t, `error`,
+		`Scan: Options: Source require local directory or file`,
+		gotError)
+
+	_, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:            filepath.Join(`testdata`, `source`),
+		PastResultFile: filepath.Join(`testdata`, `past_result.json`),
+		Source:         true,
+	})
+	gotError = ``
+	if err != nil {
+		gotError = err.Error()
+	}
+	test.Assert(t, `error with PastResultFile`,
+		`Scan: Options: Source cannot be used with PastResultFile`,
+		gotError)
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// stateFileName the name of file inside the [Options.StateDir] that
+// store the state of scan.
+const stateFileName = `state.json`
+
+// stateCheckpointInterval the interval to save the state while scanning.
+const stateCheckpointInterval = time.Minute
+
+// scanState contains the state of scan that can be resumed later.
+type scanState struct {
+	// SavedAt the time when the state saved.
+	SavedAt time.Time `json:"saved_at"`
+
+	// Result the partial result of scan.
+	Result *Result `json:"result"`
+
+	// SeenLink the links that has been scanned and their HTTP status
+	// code.
+	SeenLink map[string]int `json:"seen_link"`
+
+	// Url the URL being scanned, [Options.Url].
+	Url string `json:"url"`
+
+	// Frontier the links that has not been scanned yet.
+	Frontier []stateLink `json:"frontier"`
+
+	// Waiting the links that waiting for the same link in Frontier to
+	// be scanned.
+	Waiting []stateLink `json:"waiting,omitempty"`
+}
+
+// stateLink the linkQueue that stored in the state.
+type stateLink struct {
+	Parent  string `json:"parent,omitempty"`
+	Url     string `json:"url"`
+	Text    string `json:"text,omitempty"`
+	Element string `json:"element,omitempty"`
+
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	Count  int `json:"count,omitempty"`
+
+	IsExternal bool `json:"is_external,omitempty"`
+}
+
+func newStateLink(linkq linkQueue) stateLink {
+	return stateLink{
+		Parent:     linkq.parent(),
+		Url:        linkq.url,
+		Text:       linkq.text,
+		Element:    elementOf(linkq.kind),
+		Line:       linkq.line,
+		Column:     linkq.column,
+		Count:      linkq.count,
+		IsExternal: linkq.isExternal,
+	}
+}
+
+// linkQueue convert the stateLink back into linkQueue.
+func (slink *stateLink) linkQueue() (linkq linkQueue, err error) {
+	linkq = linkQueue{
+		url:        slink.Url,
+		text:       slink.Text,
+		kind:       kindOf(slink.Element),
+		line:       slink.Line,
+		column:     slink.Column,
+		count:      slink.Count,
+		isExternal: slink.IsExternal,
+	}
+	if slink.Parent != `` {
+		linkq.parentUrl, err = url.Parse(slink.Parent)
+		if err != nil {
+			return linkq, err
+		}
+	}
+	return linkq, nil
+}
+
+// loadState load the scan state from file [stateFileName] inside the
+// directory dir.
+// It return nil without an error if the state file does not exist.
+func loadState(dir string) (state *scanState, err error) {
+	var file = filepath.Join(dir, stateFileName)
+	var content []byte
+
+	content, err = os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	state = &scanState{}
+	err = json.Unmarshal(content, state)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, file, err)
+	}
+	if state.Result == nil {
+		state.Result = newResult()
+	}
+	if state.Result.BrokenLinks == nil {
+		state.Result.BrokenLinks = map[string][]Broken{}
+	}
+	if state.SeenLink == nil {
+		state.SeenLink = map[string]int{}
+	}
+	return state, nil
+}
+
+// save the state into file [stateFileName] inside the directory dir.
+func (state *scanState) save(dir string) (err error) {
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	content, err = json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	var file = filepath.Join(dir, stateFileName)
+	return internal.WriteFileAtomic(file, content, 0600)
+}
+
+// removeState remove the state file inside the directory dir, if its
+// exist.
+func removeState(dir string) (err error) {
+	var file = filepath.Join(dir, stateFileName)
+	err = os.Remove(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink"
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScanContext_resume(t *testing.T) {
+	const testUrl = `http://` + testAddress + `/page2`
+
+	var stateDir = t.TempDir()
+	var stateFile = filepath.Join(stateDir, `state.json`)
+	var opts = brokenlinks.Options{
+		Url:      testUrl,
+		StateDir: stateDir,
+		Cache:    jarink.NewMemoryCache(),
+	}
+
+	// Interrupt the scan before its started, so the first URL is kept
+	// in the state.
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	var got, err = brokenlinks.ScanContext(ctx, opts)
+	if !errors.Is(err, brokenlinks.ErrInterrupted) {
+		t.Fatalf(`expecting error %v, got %v`, brokenlinks.ErrInterrupted, err)
+	}
+	test.Assert(t, `interrupted result`, 0, len(got.BrokenLinks))
+
+	var content []byte
+	content, err = os.ReadFile(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state struct {
+		Url      string `json:"url"`
+		Frontier []struct {
+			Url string `json:"url"`
+		} `json:"frontier"`
+	}
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `state url`, testUrl, state.Url)
+	test.Assert(t, `state frontier`, 1, len(state.Frontier))
+	test.Assert(t, `state frontier url`, testUrl, state.Frontier[0].Url)
+
+	// Resume the scan, the result should be equal with scan without
+	// interruption.
+
+	opts.Resume = true
+	got, err = brokenlinks.Scan(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp *brokenlinks.Result
+	exp, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:   testUrl,
+		Cache: jarink.NewMemoryCache(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `resumed result`, exp, got)
+
+	_, err = os.Stat(stateFile)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf(`expecting state file removed, got %v`, err)
+	}
+}
+
+func TestScan_resumeInvalid(t *testing.T) {
+	const testUrl = `http://` + testAddress
+
+	var stateDir = t.TempDir()
+	var state = []byte(`{"url":"http://127.0.0.1:11836/page2"}`)
+	var err = os.WriteFile(filepath.Join(stateDir, `state.json`), state,
+		0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		desc     string
+		expError string
+		opts     brokenlinks.Options
+	}
+	var listCase = []testCase{{
+		desc: `without StateDir`,
+		opts: brokenlinks.Options{
+			Url:    testUrl,
+			Resume: true,
+		},
+		expError: `Scan: Options: Resume require StateDir`,
+	}, {
+		desc: `with DiskDir`,
+		opts: brokenlinks.Options{
+			Url:      testUrl,
+			StateDir: stateDir,
+			DiskDir:  stateDir,
+		},
+		expError: `Scan: Options: StateDir cannot be used with DiskDir`,
+	}, {
+		desc: `with different URL`,
+		opts: brokenlinks.Options{
+			Url:      testUrl,
+			StateDir: stateDir,
+			Resume:   true,
+		},
+		expError: `Scan: state in ` + stateDir +
+			` is for URL "http://127.0.0.1:11836/page2"`,
+	}}
+
+	for _, tcase := range listCase {
+		_, err = brokenlinks.Scan(tcase.opts)
+		var gotError string
+		if err != nil {
+			gotError = err.Error()
+		}
+		test.Assert(t, tcase.desc, tcase.expError, gotError)
+	}
+}
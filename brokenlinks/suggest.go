@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks
+
+import (
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions the maximum number of suggestions for each broken link.
+const maxSuggestions = 3
+
+// List of rank of suggestion, from the most likely.
+const (
+	// rankCase the link only differ in letter case.
+	rankCase = iota
+
+	// rankSuffix the link only differ in trailing slash, ".html"
+	// extension, or "/index.html".
+	rankSuffix
+
+	// rankSlug the link has the same last path segment in another
+	// directory.
+	rankSlug
+
+	// rankDistance the link is similar within the edit distance, where
+	// the rank is rankDistance plus the distance.
+	rankDistance
+)
+
+// suggestion contains the candidate link and its rank.
+type suggestion struct {
+	link string
+	rank int
+}
+
+// suggest set the [Broken.Suggestions] on the broken internal links with
+// status 404 Not Found or 410 Gone in the result, by comparing them with
+// the internal links that has been scanned successfully.
+// The broken links that passed to [Options.OnBroken], the links in source
+// files, and the links when scanning with [Options.DiskDir] does not have
+// suggestions.
+func (wrk *worker) suggest() {
+	if len(wrk.result.BrokenLinks) == 0 || wrk.opts.Source ||
+		wrk.opts.DiskDir != `` {
+		return
+	}
+
+	var (
+		base          = wrk.baseUrl.String()
+		listCandidate []string
+	)
+	var err = wrk.links.walkSeen(func(url string, status int) error {
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return nil
+		}
+		if !isUnder(url, base, '/') {
+			return nil
+		}
+		if wrk.local != nil {
+			url = wrk.local.path(url)
+		}
+		listCandidate = append(listCandidate, url)
+		return nil
+	})
+	if err != nil {
+		wrk.log.Warn(`suggest`, `error`, err.Error())
+		return
+	}
+	if len(listCandidate) == 0 {
+		return
+	}
+	slices.Sort(listCandidate)
+
+	var sep byte = '/'
+	if wrk.local != nil {
+		base = wrk.local.dir
+		sep = filepath.Separator
+	}
+	for _, listBroken := range wrk.result.BrokenLinks {
+		for x, broken := range listBroken {
+			if broken.Code != http.StatusNotFound &&
+				broken.Code != http.StatusGone {
+				continue
+			}
+			if !isUnder(broken.Link, base, sep) {
+				continue
+			}
+			listBroken[x].Suggestions = suggestLinks(base,
+				broken.Link, listCandidate)
+		}
+	}
+}
+
+// isUnder return true if the link is equal to base or inside it, where
+// sep is the separator after base, so the base "http://host" does not
+// match with "http://host.evil" or "http://host:8080".
+func isUnder(link, base string, sep byte) bool {
+	var rest, ok = strings.CutPrefix(link, base)
+	if !ok {
+		return false
+	}
+	return rest == `` || rest[0] == sep || strings.HasSuffix(base, string(sep))
+}
+
+// suggestLinks return the links in listCandidate that likely to be the
+// intended target of the broken link, based on the following rules,
+// ordered from the most likely,
+//
+//   - the link only differ in letter case,
+//   - the link only differ in trailing slash, ".html" extension, or
+//     "/index.html",
+//   - the link has the same last path segment in another directory, or
+//   - the link path is similar within the edit distance.
+//
+// All of the links must start with base.
+func suggestLinks(base, link string, listCandidate []string) (
+	listLink []string,
+) {
+	var (
+		linkPath    = filepath.ToSlash(strings.TrimPrefix(link, base))
+		linkLower   = strings.ToLower(linkPath)
+		linkTrim    = trimLinkSuffix(linkLower)
+		linkSlug    = path.Base(linkTrim)
+		maxDistance = min(3, len(linkTrim)/3)
+
+		listSuggest []suggestion
+	)
+	for _, candidate := range listCandidate {
+		var (
+			candPath  = filepath.ToSlash(strings.TrimPrefix(candidate, base))
+			candLower = strings.ToLower(candPath)
+			candTrim  = trimLinkSuffix(candLower)
+			rank      = -1
+		)
+		switch {
+		case candPath == linkPath:
+			continue
+		case candLower == linkLower:
+			rank = rankCase
+		case candTrim == linkTrim:
+			rank = rankSuffix
+		case linkSlug != `/` && linkSlug != `.` &&
+			path.Base(candTrim) == linkSlug:
+			rank = rankSlug
+		default:
+			var diff = len(candTrim) - len(linkTrim)
+			if diff > maxDistance || -diff > maxDistance {
+				continue
+			}
+			var distance = editDistance(linkTrim, candTrim)
+			if distance <= maxDistance {
+				rank = rankDistance + distance
+			}
+		}
+		if rank < 0 {
+			continue
+		}
+		listSuggest = append(listSuggest, suggestion{
+			link: candidate,
+			rank: rank,
+		})
+	}
+
+	slices.SortStableFunc(listSuggest, func(a, b suggestion) int {
+		return a.rank - b.rank
+	})
+	for x, sg := range listSuggest {
+		if x == maxSuggestions {
+			break
+		}
+		listLink = append(listLink, sg.link)
+	}
+	return listLink
+}
+
+// trimLinkSuffix remove the "/index.html", ".html", and trailing slash
+// from the link path.
+func trimLinkSuffix(linkPath string) string {
+	linkPath = strings.TrimSuffix(linkPath, `/index.html`)
+	linkPath = strings.TrimSuffix(linkPath, `.html`)
+	return strings.TrimSuffix(linkPath, `/`)
+}
+
+// editDistance return the Levenshtein distance between a and b, the
+// minimum number of single character insertions, deletions, or
+// substitutions to change a into b.
+func editDistance(a, b string) int {
+	var (
+		ra   = []rune(a)
+		rb   = []rune(b)
+		prev = make([]int, len(rb)+1)
+		curr = make([]int, len(rb)+1)
+	)
+	for y := range prev {
+		prev[y] = y
+	}
+	for x := 1; x <= len(ra); x++ {
+		curr[0] = x
+		for y := 1; y <= len(rb); y++ {
+			var cost = 1
+			if ra[x-1] == rb[y-1] {
+				cost = 0
+			}
+			curr[y] = min(prev[y]+1, curr[y-1]+1, prev[y-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
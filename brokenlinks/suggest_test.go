@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package brokenlinks_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+func TestScan_suggestions(t *testing.T) {
+	var dir = filepath.Join(`testdata`, `suggest`)
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     dir,
+		NoCache: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Broken{
+		filepath.Join(dir, `index.html`): {{
+			Link:        filepath.Join(dir, `About.html`),
+			Text:        `Case mismatch`,
+			Element:     `a@href`,
+			Code:        http.StatusNotFound,
+			Line:        12,
+			Column:      5,
+			Count:       1,
+			Suggestions: []string{filepath.Join(dir, `about.html`)},
+		}, {
+			Link:        filepath.Join(dir, `abuot.html`),
+			Text:        `Typo`,
+			Element:     `a@href`,
+			Code:        http.StatusNotFound,
+			Line:        14,
+			Column:      5,
+			Count:       1,
+			Suggestions: []string{filepath.Join(dir, `about.html`)},
+		}, {
+			Link:    filepath.Join(dir, `install.html`),
+			Text:    `Other directory`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    13,
+			Column:  5,
+			Count:   1,
+			Suggestions: []string{
+				filepath.Join(dir, `docs`, `install.html`),
+			},
+		}, {
+			Link:    filepath.Join(dir, `unknown.html`),
+			Text:    `Unknown`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    15,
+			Column:  5,
+			Count:   1,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, exp, got.BrokenLinks)
+}
+
+// TestScan_suggestionsOtherHost test that the links in other host, which
+// URL start with the URL of website, are not suggested.
+// For example, the website "http://127.0.0.1:5000" and other website
+// "http://127.0.0.1:50000".
+func TestScan_suggestionsOtherHost(t *testing.T) {
+	var site, other net.Listener
+	for port := 5000; port < 6500 && other == nil; port++ {
+		var err error
+		site, err = net.Listen(`tcp`, fmt.Sprintf(`127.0.0.1:%d`, port))
+		if err != nil {
+			continue
+		}
+		other, err = net.Listen(`tcp`, fmt.Sprintf(`127.0.0.1:%d0`, port))
+		if err != nil {
+			site.Close()
+			site = nil
+		}
+	}
+	if other == nil {
+		t.Skip(`no available pair of port`)
+	}
+
+	var siteUrl = `http://` + site.Addr().String()
+	var otherUrl = `http://` + other.Addr().String()
+
+	var siteSrv = &http.Server{
+		Handler: http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != `/` {
+				http.NotFound(resp, req)
+				return
+			}
+			resp.Header().Set(`Content-Type`, `text/html`)
+			fmt.Fprintf(resp, `<html><body>`+
+				`<a href="/docs/install.html">Install</a>`+
+				`<a href="%s/install.html">Other</a>`+
+				`</body></html>`, otherUrl)
+		}),
+	}
+	var otherSrv = &http.Server{
+		Handler: http.HandlerFunc(func(resp http.ResponseWriter, _ *http.Request) {
+			resp.Header().Set(`Content-Type`, `text/html`)
+			fmt.Fprint(resp, `<html><body>Other</body></html>`)
+		}),
+	}
+	go func() {
+		_ = siteSrv.Serve(site)
+	}()
+	go func() {
+		_ = otherSrv.Serve(other)
+	}()
+	t.Cleanup(func() {
+		_ = siteSrv.Close()
+		_ = otherSrv.Close()
+	})
+
+	var got, err = brokenlinks.Scan(brokenlinks.Options{
+		Url:     siteUrl,
+		NoCache: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exp = map[string][]brokenlinks.Broken{
+		siteUrl: {{
+			Link:    siteUrl + `/docs/install.html`,
+			Text:    `Install`,
+			Element: `a@href`,
+			Code:    http.StatusNotFound,
+			Line:    1,
+			Column:  13,
+			Count:   1,
+		}},
+	}
+	test.Assert(t, `BrokenLinks`, exp, got.BrokenLinks)
+}
@@ -4,29 +4,53 @@
 package brokenlinks
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"maps"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
-	"os"
+	"path"
 	"slices"
 	"strings"
 	"sync"
 	"time"
 
-	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 
 	"git.sr.ht/~shulhan/jarink"
+	"git.sr.ht/~shulhan/jarink/internal"
 )
 
+// cacheCheckpointInterval the interval to save the cache while scanning.
+const cacheCheckpointInterval = time.Minute
+
 type worker struct {
-	// seenLink store the URL being or has been scanned and its HTTP
-	// status code.
-	seenLink map[string]int
+	// ctx the context of scan, cancel it to interrupt the scan.
+	ctx context.Context
+
+	// links store the URL being or has been scanned and its HTTP
+	// status code, and the queue of links waiting to be scanned.
+	links linkStore
+
+	// errStore the first error from links that stop the scan.
+	errStore error
+
+	// cancel the ctx when the links failed.
+	cancel context.CancelFunc
+
+	// scanning store the links that being scanned, by its URL.
+	// The number of links being scanned is limited by
+	// [Options.MaxConcurrent].
+	scanning map[string]linkQueue
 
 	// resultq channel that collect result from scanning.
 	resultq chan map[string]linkQueue
@@ -44,9 +68,35 @@ type worker struct {
 	baseUrl *url.URL
 
 	// cache of scanned links.
-	cache *jarink.Cache
+	cache jarink.CacheStore
+
+	// local the server for local directory [Options.Url].
+	local *localServer
+
+	// httpsChecker check the insecure subresources in the page, if
+	// [Options.MixedContent] is true and the URL scheme is "https".
+	httpsChecker *httpsChecker
+
+	// seoPages contains the internal HTML pages for SEO audit, by its
+	// URL, if [Options.Seo] is true.
+	seoPages map[string]pageMeta
+
+	// seoRedirects contains the status of last redirect response, by
+	// the URL of scanned link that redirected to other page, for
+	// checking the canonical link on SEO audit.
+	seoRedirects map[string]int
+
+	// state the scan state loaded from [Options.StateDir] when
+	// [Options.Resume] is true.
+	state *scanState
+
+	// cacheSavedAt the last time the cache saved by checkpointCache.
+	cacheSavedAt time.Time
+
+	// stateSavedAt the last time the state saved by checkpointState.
+	stateSavedAt time.Time
 
-	log *log.Logger
+	log *slog.Logger
 
 	httpc *http.Client
 
@@ -54,39 +104,93 @@ type worker struct {
 
 	// wg sync the goroutine scanner.
 	wg sync.WaitGroup
+
+	// isCacheOwner true if the cache opened by worker, not from
+	// [Options.Cache], so it should be closed after scan.
+	isCacheOwner bool
 }
 
-func newWorker(opts Options) (wrk *worker, err error) {
+// newHttpClient create the HTTP client for scanning the links.
+// If insecure is true, the invalid server certificate is not reported as
+// error.
+func newHttpClient(insecure bool) *http.Client {
 	var netDial = &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
 	}
 	var tlsConfig = &tls.Config{
-		InsecureSkipVerify: opts.Insecure,
+		InsecureSkipVerify: insecure,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:           netDial.DialContext,
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     true,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConns:          100,
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   10 * time.Second,
+		},
 	}
+}
 
+func newWorker(ctx context.Context, opts Options) (wrk *worker, err error) {
 	wrk = &worker{
-		opts:     opts,
-		seenLink: map[string]int{},
-		resultq:  make(chan map[string]linkQueue, 100),
-		result:   newResult(),
-		log:      log.New(os.Stderr, ``, log.LstdFlags),
-		httpc: &http.Client{
-			Transport: &http.Transport{
-				DialContext:           netDial.DialContext,
-				ExpectContinueTimeout: 1 * time.Second,
-				ForceAttemptHTTP2:     true,
-				IdleConnTimeout:       90 * time.Second,
-				MaxIdleConns:          100,
-				TLSClientConfig:       tlsConfig,
-				TLSHandshakeTimeout:   10 * time.Second,
-			},
-		},
+		opts:         opts,
+		scanning:     map[string]linkQueue{},
+		resultq:      make(chan map[string]linkQueue, 100),
+		result:       newResult(),
+		cacheSavedAt: time.Now(),
+		stateSavedAt: time.Now(),
+		log:          opts.Logger,
+		httpc:        newHttpClient(opts.Insecure),
 	}
 
-	wrk.cache, err = jarink.LoadCache()
-	if err != nil {
-		return nil, err
+	wrk.ctx, wrk.cancel = context.WithCancel(ctx)
+
+	switch {
+	case opts.Cache != nil:
+		wrk.cache = opts.Cache
+	case !opts.NoCache:
+		wrk.cache, err = jarink.OpenCache(opts.CacheFile)
+		if err != nil {
+			return nil, err
+		}
+		wrk.isCacheOwner = true
+	}
+
+	if opts.localDir != `` && !opts.Source {
+		wrk.local, err = newLocalServer(opts.localDir)
+		if err != nil {
+			return nil, err
+		}
+		wrk.opts.scanUrl, err = url.Parse(wrk.local.url)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if (opts.Graph || opts.Inventory) && !opts.Source {
+		wrk.result.Graph = newGraph()
+	}
+	if opts.Seo {
+		wrk.seoPages = map[string]pageMeta{}
+		wrk.seoRedirects = map[string]int{}
+	}
+	if opts.Headers {
+		wrk.result.HeadersSummary = &HeadersSummary{}
+	}
+	if opts.MixedContent && wrk.opts.scanUrl.Scheme == `https` {
+		wrk.httpsChecker = newHttpsChecker(wrk.httpc)
+	}
+
+	if opts.DiskDir != `` {
+		wrk.links, err = openBoltLinkStore(opts.DiskDir)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		wrk.links = newMemoryLinkStore()
 	}
 
 	wrk.baseUrl = &url.URL{
@@ -94,18 +198,23 @@ func newWorker(opts Options) (wrk *worker, err error) {
 		Host:   wrk.opts.scanUrl.Host,
 	}
 
+	if opts.Resume {
+		wrk.state, err = loadState(opts.StateDir)
+		if err != nil {
+			return nil, err
+		}
+		if wrk.state != nil && wrk.state.Url != opts.scanUrl.String() {
+			return nil, fmt.Errorf(`state in %s is for URL %q`,
+				opts.StateDir, wrk.state.Url)
+		}
+	}
+
 	if opts.PastResultFile == "" {
 		// Run with normal scan.
 		return wrk, nil
 	}
 
-	pastresult, err := os.ReadFile(opts.PastResultFile)
-	if err != nil {
-		return nil, err
-	}
-
-	wrk.pastResult = newResult()
-	err = json.Unmarshal(pastresult, &wrk.pastResult)
+	wrk.pastResult, err = loadResult(opts.PastResultFile)
 	if err != nil {
 		return nil, err
 	}
@@ -113,10 +222,34 @@ func newWorker(opts Options) (wrk *worker, err error) {
 	return wrk, nil
 }
 
+// close the local server, the links, save the cache, and close the cache
+// if its opened by worker.
+func (wrk *worker) close() (err error) {
+	wrk.cancel()
+
+	if wrk.local != nil {
+		err = wrk.local.close()
+	}
+	err = errors.Join(err, wrk.links.close())
+	if wrk.cache == nil {
+		return err
+	}
+	err = errors.Join(err, wrk.cache.Save())
+	if !wrk.isCacheOwner {
+		return err
+	}
+	return errors.Join(err, wrk.cache.Close())
+}
+
 func (wrk *worker) run() (result *Result, err error) {
-	if wrk.pastResult == nil {
+	switch {
+	case wrk.state != nil:
+		result, err = wrk.resume()
+	case wrk.opts.Source:
+		result, err = wrk.scanSource()
+	case wrk.pastResult == nil:
 		result, err = wrk.scanAll()
-	} else {
+	default:
 		result, err = wrk.scanPastResult()
 	}
 	return result, err
@@ -130,82 +263,207 @@ func (wrk *worker) scanAll() (result *Result, err error) {
 		url:       wrk.opts.scanUrl.String(),
 		status:    http.StatusProcessing,
 	}
-	wrk.seenLink[firstLinkq.url] = http.StatusProcessing
-
-	wrk.wg.Add(1)
-	go func() {
-		var resultq = wrk.scan(firstLinkq)
-		wrk.pushResult(resultq)
-	}()
+	wrk.queue(firstLinkq)
 	wrk.wg.Wait()
 
-	var resultq = <-wrk.resultq
+	var resultq map[string]linkQueue
+	select {
+	case resultq = <-wrk.resultq:
+	case <-wrk.ctx.Done():
+	}
+	if wrk.ctx.Err() != nil {
+		return wrk.interrupt()
+	}
 	for _, linkq := range resultq {
+		wrk.recordGraph(linkq)
+		wrk.recordSitemap(linkq)
+		wrk.recordSeo(linkq)
+		wrk.recordIssues(linkq)
 		if linkq.url == firstLinkq.url {
 			if linkq.errScan != nil {
 				return nil, linkq.errScan
 			}
-			wrk.seenLink[linkq.url] = linkq.status
+			delete(wrk.scanning, linkq.url)
+			wrk.setSeen(linkq.url, linkq.status)
 			continue
 		}
 
-		if linkq.isExternal {
-			var scannedLink = wrk.cache.Get(linkq.url)
-			if scannedLink != nil {
-				linkq.status = scannedLink.ResponseCode
-				wrk.seen(linkq)
-				continue
-			}
+		if wrk.fromCache(&linkq) {
+			wrk.seen(linkq)
+			continue
 		}
 
-		wrk.seenLink[linkq.url] = http.StatusProcessing
-		wrk.wg.Add(1)
-		go func() {
-			var resultq = wrk.scan(linkq)
-			wrk.pushResult(resultq)
-		}()
+		wrk.queue(linkq)
 	}
 
-	wrk.processAndWait()
-	return wrk.result, nil
+	return wrk.processAndWait()
 }
 
 // scanPastResult scan only pages reported inside
 // [Result.BrokenLinks].
 func (wrk *worker) scanPastResult() (result *Result, err error) {
 	for page := range wrk.pastResult.BrokenLinks {
+		if wrk.local != nil {
+			page = wrk.local.link(page)
+		}
 		var linkq = linkQueue{
 			parentUrl: nil,
 			url:       page,
 			status:    http.StatusProcessing,
 		}
-		wrk.seenLink[linkq.url] = http.StatusProcessing
-		wrk.wg.Add(1)
-		go func() {
-			var resultq = wrk.scan(linkq)
-			wrk.pushResult(resultq)
-		}()
+		wrk.queue(linkq)
 	}
 
-	wrk.processAndWait()
-	return wrk.result, nil
+	return wrk.processAndWait()
 }
 
-func (wrk *worker) processAndWait() {
+// resume continue the scan from the state loaded from
+// [Options.StateDir].
+func (wrk *worker) resume() (result *Result, err error) {
+	var state = wrk.state
+	wrk.state = nil
+
+	wrk.log.Info(`resume`, `url`, state.Url, `saved_at`, state.SavedAt,
+		`frontier`, len(state.Frontier), `seen`, len(state.SeenLink))
+
+	// The link that has not been completed scanned, is scanned again
+	// when found.
+	maps.DeleteFunc(state.SeenLink, func(_ string, status int) bool {
+		return status == http.StatusProcessing
+	})
+	wrk.links = &memoryLinkStore{
+		seenLink: state.SeenLink,
+		waiting:  map[string][]linkQueue{},
+	}
+	wrk.result = state.Result
+	if (wrk.opts.Graph || wrk.opts.Inventory) && !wrk.opts.Source {
+		if wrk.result.Graph == nil {
+			wrk.result.Graph = newGraph()
+		} else {
+			wrk.result.Graph.reindex()
+		}
+	}
+
+	for _, slink := range state.Waiting {
+		var linkq linkQueue
+		linkq, err = slink.linkQueue()
+		if err != nil {
+			return nil, err
+		}
+		linkq.status = http.StatusProcessing
+		err = wrk.links.wait(linkq)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, slink := range state.Frontier {
+		var linkq linkQueue
+		linkq, err = slink.linkQueue()
+		if err != nil {
+			return nil, err
+		}
+		wrk.queue(linkq)
+	}
+
+	return wrk.processAndWait()
+}
+
+// queue mark the link as being scanned and scan it, or push it to the
+// links if the number of links being scanned has reached
+// [Options.MaxConcurrent].
+func (wrk *worker) queue(linkq linkQueue) {
+	wrk.setSeen(linkq.url, http.StatusProcessing)
+	if len(wrk.scanning) < wrk.opts.MaxConcurrent {
+		wrk.startScan(linkq)
+		return
+	}
+	var err = wrk.links.push(linkq)
+	if err != nil {
+		wrk.fail(err)
+	}
+}
+
+// scanNext scan the links in the queue until the number of links being
+// scanned reach [Options.MaxConcurrent].
+func (wrk *worker) scanNext() {
+	for len(wrk.scanning) < wrk.opts.MaxConcurrent {
+		var linkq, ok, err = wrk.links.pop()
+		if err != nil {
+			wrk.fail(err)
+			return
+		}
+		if !ok {
+			return
+		}
+		wrk.startScan(linkq)
+	}
+}
+
+// startScan scan the link in new goroutine.
+func (wrk *worker) startScan(linkq linkQueue) {
+	wrk.scanning[linkq.url] = linkq
+	wrk.wg.Add(1)
+	go func() {
+		var resultq = wrk.scan(linkq)
+		wrk.pushResult(resultq)
+	}()
+}
+
+// seenStatus return the status of link and true if the link has been
+// seen.
+func (wrk *worker) seenStatus(url string) (status int, ok bool) {
+	var err error
+	status, ok, err = wrk.links.seen(url)
+	if err != nil {
+		wrk.fail(err)
+	}
+	return status, ok
+}
+
+// setSeen set the status of seen link.
+func (wrk *worker) setSeen(url string, status int) {
+	var err = wrk.links.setSeen(url, status)
+	if err != nil {
+		wrk.fail(err)
+	}
+}
+
+// fail stop the scan because of error on links.
+func (wrk *worker) fail(err error) {
+	if wrk.errStore == nil {
+		wrk.errStore = err
+	}
+	wrk.cancel()
+}
+
+func (wrk *worker) processAndWait() (result *Result, err error) {
 	var tick = time.NewTicker(500 * time.Millisecond)
-	var listWaitStatus []linkQueue
+	defer tick.Stop()
 	var isScanning = true
 	for isScanning {
 		select {
 		case resultq := <-wrk.resultq:
-			listWaitStatus = wrk.processResult(resultq, listWaitStatus)
+			if wrk.ctx.Err() != nil {
+				// The result may be incomplete, scan it
+				// again on resume.
+				return wrk.interrupt()
+			}
+			wrk.processResult(resultq)
+			wrk.scanNext()
+			wrk.checkpointCache()
+			wrk.checkpointState()
+
+		case <-wrk.ctx.Done():
+			return wrk.interrupt()
 
 		case <-tick.C:
-			wrk.wg.Wait()
-			if len(wrk.resultq) != 0 {
+			wrk.checkpointCache()
+			wrk.checkpointState()
+			wrk.scanNext()
+			if len(wrk.scanning) != 0 || len(wrk.resultq) != 0 {
 				continue
 			}
-			if len(listWaitStatus) != 0 {
+			if wrk.links.nwait() != 0 {
 				// There are links that still waiting for
 				// scanning to be completed.
 				continue
@@ -213,7 +471,117 @@ func (wrk *worker) processAndWait() {
 			isScanning = false
 		}
 	}
+	wrk.wg.Wait()
+	wrk.suggest()
+	wrk.finalizeGraph()
+	wrk.finalizeInventory()
+	wrk.finalizeSeo()
 	wrk.result.sort()
+
+	if wrk.opts.StateDir != `` {
+		err = removeState(wrk.opts.StateDir)
+		if err != nil {
+			wrk.log.Warn(`remove state`, `error`, err.Error())
+		}
+	}
+	return wrk.result, nil
+}
+
+// interrupt stop the scan, save the state into [Options.StateDir], and
+// return the partial result with [ErrInterrupted].
+// If the scan stopped because of error on links, it return the error
+// instead.
+func (wrk *worker) interrupt() (result *Result, err error) {
+	// Wait for the goroutines scanner to return, their fetch is
+	// cancelled by context and their result is ignored.
+	wrk.wg.Wait()
+
+	if wrk.errStore != nil {
+		return nil, wrk.errStore
+	}
+
+	if wrk.opts.StateDir != `` {
+		err = wrk.saveState()
+		if err != nil {
+			wrk.log.Error(`save state`, `error`, err.Error())
+		}
+	}
+	wrk.finalizeGraph()
+	wrk.finalizeInventory()
+	wrk.finalizeSeo()
+	wrk.result.sort()
+	return wrk.result, ErrInterrupted
+}
+
+// checkpointState save the state periodically, every
+// [stateCheckpointInterval], so the scan can be resumed when the process
+// is killed.
+func (wrk *worker) checkpointState() {
+	if wrk.opts.StateDir == `` {
+		return
+	}
+	var now = time.Now()
+	if now.Before(wrk.stateSavedAt.Add(stateCheckpointInterval)) {
+		return
+	}
+	wrk.stateSavedAt = now
+
+	var err = wrk.saveState()
+	if err != nil {
+		wrk.log.Warn(`checkpoint state`, `error`, err.Error())
+	}
+}
+
+// saveState save the links being scanned and in the queue, seen links,
+// and partial result into [Options.StateDir].
+func (wrk *worker) saveState() error {
+	// The StateDir cannot be used with DiskDir, so the links always
+	// stored in memory.
+	var links = wrk.links.(*memoryLinkStore)
+
+	var state = scanState{
+		SavedAt:  internal.TimeNow(),
+		Result:   wrk.result,
+		SeenLink: links.seenLink,
+		Url:      wrk.opts.scanUrl.String(),
+		Frontier: make([]stateLink, 0,
+			len(wrk.scanning)+len(links.queue)),
+	}
+	for _, linkq := range wrk.scanning {
+		state.Frontier = append(state.Frontier, newStateLink(linkq))
+	}
+	for _, linkq := range links.queue {
+		state.Frontier = append(state.Frontier, newStateLink(linkq))
+	}
+	slices.SortFunc(state.Frontier, func(a, b stateLink) int {
+		return strings.Compare(a.Url, b.Url)
+	})
+	for _, url := range slices.Sorted(maps.Keys(links.waiting)) {
+		for _, linkq := range links.waiting[url] {
+			state.Waiting = append(state.Waiting,
+				newStateLink(linkq))
+		}
+	}
+	return state.save(wrk.opts.StateDir)
+}
+
+// checkpointCache save the cache periodically, every
+// [cacheCheckpointInterval], so the scanned external links is not lost
+// when the scan is interrupted.
+func (wrk *worker) checkpointCache() {
+	if wrk.cache == nil {
+		return
+	}
+	var now = time.Now()
+	if now.Before(wrk.cacheSavedAt.Add(cacheCheckpointInterval)) {
+		return
+	}
+	wrk.cacheSavedAt = now
+
+	var err = wrk.cache.Save()
+	if err != nil {
+		wrk.log.Warn(`checkpoint cache`, `error`, err.Error())
+	}
 }
 
 // processResult the resultq contains the original URL being scanned
@@ -224,41 +592,38 @@ func (wrk *worker) processAndWait() {
 //	"http://example.tld/page": {status=0}
 //	"http://example.tld/image.png": {status=0}
 //	"http://bad:domain/image.png": {status=700}
-func (wrk *worker) processResult(
-	resultq map[string]linkQueue, listWaitStatus []linkQueue,
-) (
-	newList []linkQueue,
-) {
+func (wrk *worker) processResult(resultq map[string]linkQueue) {
 	for _, linkq := range resultq {
+		wrk.recordGraph(linkq)
+		wrk.recordSitemap(linkq)
+		wrk.recordSeo(linkq)
+		wrk.recordIssues(linkq)
+
 		// Process the scanned page first.
 
 		if linkq.status != 0 {
-			wrk.seen(linkq)
-			if linkq.isExternal && linkq.status != StatusBadLink {
-				wrk.cache.Set(linkq.url, linkq.status, linkq.size)
+			delete(wrk.scanning, linkq.url)
+			if slices.Contains(wrk.opts.ignoreStatus, linkq.status) {
+				// Assume the link as passed.
+				wrk.setSeen(linkq.url, http.StatusOK)
+			} else {
+				wrk.seen(linkq)
+				wrk.toCache(linkq)
 			}
+			wrk.doneWaiting(linkq.url)
 			continue
 		}
 
 		// Now process the links inside the page.
 
-		if linkq.isExternal {
-			var scannedLink = wrk.cache.Get(linkq.url)
-			if scannedLink != nil {
-				linkq.status = scannedLink.ResponseCode
-				wrk.seen(linkq)
-				continue
-			}
+		if wrk.fromCache(&linkq) {
+			wrk.seen(linkq)
+			continue
 		}
 
-		seenStatus, seen := wrk.seenLink[linkq.url]
+		seenStatus, seen := wrk.seenStatus(linkq.url)
 		if !seen {
-			wrk.seenLink[linkq.url] = http.StatusProcessing
-			wrk.wg.Add(1)
-			go func() {
-				var resultq = wrk.scan(linkq)
-				wrk.pushResult(resultq)
-			}()
+			wrk.queue(linkq)
 			continue
 		}
 		if seenStatus >= http.StatusBadRequest {
@@ -271,27 +636,298 @@ func (wrk *worker) processResult(
 			// not an error.
 			continue
 		}
-		// The link being processed by other goroutine.
+		// The link being processed by other goroutine, wait
+		// until its scanned.
 		linkq.status = seenStatus
-		newList = append(newList, linkq)
+		var err = wrk.links.wait(linkq)
+		if err != nil {
+			wrk.fail(err)
+		}
 	}
-	for _, linkq := range listWaitStatus {
-		seenStatus := wrk.seenLink[linkq.url]
-		if seenStatus >= http.StatusBadRequest {
-			linkq.status = seenStatus
-			wrk.markBroken(linkq)
-			continue
+}
+
+// doneWaiting mark the links that waiting for the status of url as broken
+// if the url is broken.
+func (wrk *worker) doneWaiting(url string) {
+	var listWait, err = wrk.links.done(url)
+	if err != nil {
+		wrk.fail(err)
+		return
+	}
+	if len(listWait) == 0 {
+		return
+	}
+	var seenStatus, _ = wrk.seenStatus(url)
+	if seenStatus < http.StatusBadRequest {
+		return
+	}
+	for _, linkq := range listWait {
+		linkq.status = seenStatus
+		wrk.markBroken(linkq)
+	}
+}
+
+// recordGraph add the link from the result of scan into
+// [Result.Graph].
+// The link with non-zero status that being scanned is the scanned page or
+// image itself, while other links are the links found inside the page.
+func (wrk *worker) recordGraph(linkq linkQueue) {
+	var graph = wrk.result.Graph
+	if graph == nil {
+		return
+	}
+	if linkq.status != 0 {
+		var _, isScanned = wrk.scanning[linkq.url]
+		if isScanned {
+			graph.setNode(linkq)
+			return
 		}
-		if seenStatus >= http.StatusOK {
+	}
+	graph.addEdge(linkq)
+	if linkq.status != 0 {
+		graph.setNode(linkq)
+	}
+}
+
+// finalizeGraph set the status of nodes that are not scanned by this
+// worker, for example the external links from cache, and the depth of
+// each node in [Result.Graph].
+func (wrk *worker) finalizeGraph() {
+	var graph = wrk.result.Graph
+	if graph == nil {
+		return
+	}
+	for x, node := range graph.Nodes {
+		if node.Status != 0 {
 			continue
 		}
-		if seenStatus == http.StatusProcessing {
-			// Scanning still in progress.
-			newList = append(newList, linkq)
-			continue
+		var status, _ = wrk.seenStatus(node.Url)
+		if status != http.StatusProcessing {
+			graph.Nodes[x].Status = status
 		}
 	}
-	return newList
+	var rename func(string) string
+	if wrk.local != nil {
+		rename = wrk.local.path
+	}
+	graph.finalize(wrk.opts.scanUrl.String(), rename)
+}
+
+// recordSitemap add the scanned page into [Result.Sitemap] if its an
+// internal HTML page with status 200, does not have robots "noindex",
+// and its canonical link refer to itself.
+func (wrk *worker) recordSitemap(linkq linkQueue) {
+	if !wrk.opts.Sitemap || wrk.opts.Source {
+		return
+	}
+	if !wrk.isScannedHtml(linkq) {
+		return
+	}
+	if linkq.meta.noIndex || !wrk.isCanonical(linkq.url, linkq.meta.canonical) {
+		return
+	}
+
+	var item = SitemapUrl{
+		Loc:     linkq.url,
+		LastMod: w3cDatetime(linkq.meta.lastModified),
+	}
+	if linkq.redirectUrl != `` {
+		// The page redirected to the same path with or without
+		// trailing slash.
+		item.Loc = linkq.redirectUrl
+	}
+	if wrk.local != nil {
+		item.Loc = wrk.local.urlPath(linkq.url)
+	}
+	wrk.result.Sitemap = append(wrk.result.Sitemap, item)
+}
+
+// isScannedHtml return true if the link is the internal HTML page with
+// status 200 that has been scanned by this worker, and not redirected to
+// other page.
+func (wrk *worker) isScannedHtml(linkq linkQueue) bool {
+	if linkq.status != http.StatusOK || linkq.isExternal ||
+		linkq.kind == atom.Img || linkq.isRedirected() {
+		return false
+	}
+	var _, isScanned = wrk.scanning[linkq.url]
+	if !isScanned {
+		return false
+	}
+	var mediaType, _, _ = mime.ParseMediaType(linkq.contentType)
+	return mediaType == `text/html` || mediaType == `application/xhtml+xml`
+}
+
+// isCanonical return true if the page does not have canonical link, or
+// its canonical link refer to the page itself.
+// When scanning local directory, only the path of canonical link is
+// compared, since the canonical link may use the URL of website.
+func (wrk *worker) isCanonical(page, canonical string) bool {
+	if canonical == `` {
+		return true
+	}
+	var pageUrl, err = url.Parse(page)
+	if err != nil {
+		return false
+	}
+	var canonicalUrl *url.URL
+	canonicalUrl, err = pageUrl.Parse(canonical)
+	if err != nil {
+		// Invalid canonical link is ignored.
+		return true
+	}
+	if wrk.local == nil {
+		if canonicalUrl.Scheme != pageUrl.Scheme ||
+			canonicalUrl.Host != pageUrl.Host {
+			return false
+		}
+	}
+	return trimLinkSuffix(canonicalUrl.Path) == trimLinkSuffix(pageUrl.Path)
+}
+
+// recordSeo store the meta of scanned internal HTML page with status 200
+// for SEO audit, or the status of redirect if the scanned link redirected
+// to other page.
+func (wrk *worker) recordSeo(linkq linkQueue) {
+	if wrk.seoPages == nil {
+		return
+	}
+	if linkq.isRedirected() {
+		var _, isScanned = wrk.scanning[linkq.url]
+		if isScanned {
+			wrk.seoRedirects[linkq.url] = linkq.redirectStatus
+		}
+		return
+	}
+	if !wrk.isScannedHtml(linkq) {
+		return
+	}
+	wrk.seoPages[linkq.url] = linkq.meta
+}
+
+// recordIssues store the issues found on the scanned internal HTML page
+// with status 200 into [Result.Issues], and count the page in
+// [Result.HeadersSummary] if the headers is audited.
+func (wrk *worker) recordIssues(linkq linkQueue) {
+	if !wrk.isScannedHtml(linkq) {
+		return
+	}
+	if wrk.result.HeadersSummary != nil {
+		wrk.result.HeadersSummary.add(linkq.issues)
+	}
+	if len(linkq.issues) == 0 {
+		return
+	}
+	wrk.addIssues(linkq.url, linkq.issues)
+}
+
+// addIssues append the issues of page into [Result.Issues].
+// When scanning local directory, the page URL is replaced with its path.
+func (wrk *worker) addIssues(pageUrl string, listIssue []Issue) {
+	if wrk.local != nil {
+		pageUrl = wrk.local.path(pageUrl)
+	}
+	if wrk.result.Issues == nil {
+		wrk.result.Issues = map[string][]Issue{}
+	}
+	wrk.result.Issues[pageUrl] = append(wrk.result.Issues[pageUrl],
+		listIssue...)
+}
+
+// finalizeSeo audit the pages recorded by recordSeo and store the issues
+// into [Result.Issues].
+func (wrk *worker) finalizeSeo() {
+	if wrk.seoPages == nil {
+		return
+	}
+	var listPage = make([]seoPage, 0, len(wrk.seoPages))
+	for pageUrl, meta := range wrk.seoPages {
+		var page = seoPage{
+			url:  pageUrl,
+			meta: meta,
+			isIndexable: !meta.noIndex &&
+				wrk.isCanonical(pageUrl, meta.canonical),
+		}
+		if meta.canonicalUrl != `` {
+			var status, _ = wrk.seenStatus(meta.canonicalUrl)
+			if status != http.StatusProcessing {
+				page.canonicalStatus = status
+			}
+			var redirectStatus, isRedirected = wrk.seoRedirects[meta.canonicalUrl]
+			if isRedirected {
+				page.canonicalStatus = redirectStatus
+			}
+		}
+		listPage = append(listPage, page)
+	}
+
+	var issues = auditSeo(listPage)
+	for pageUrl, listIssue := range issues {
+		if wrk.local != nil {
+			for x, issue := range listIssue {
+				if issue.Link != `` {
+					listIssue[x].Link = wrk.local.path(issue.Link)
+				}
+			}
+		}
+		wrk.addIssues(pageUrl, listIssue)
+	}
+}
+
+// finalizeInventory create the [Result.Inventory] from the graph.
+// The graph is removed from result if its not requested.
+func (wrk *worker) finalizeInventory() {
+	if !wrk.opts.Inventory || wrk.result.Graph == nil {
+		return
+	}
+	wrk.result.Inventory = newInventory(wrk.result.Graph)
+	if !wrk.opts.Graph {
+		wrk.result.Graph = nil
+	}
+}
+
+// fromCache set the status of external link from cache.
+// It return true if the link found in the cache and not expired.
+func (wrk *worker) fromCache(linkq *linkQueue) bool {
+	if !linkq.isExternal || wrk.cache == nil || wrk.opts.RefreshCache {
+		return false
+	}
+	var scannedLink, err = wrk.cache.Get(linkq.url)
+	if err != nil {
+		wrk.log.Warn(`get cache`, `url`, linkq.url, `error`, err.Error())
+		return false
+	}
+	if scannedLink == nil {
+		return false
+	}
+	var now = internal.TimeNow()
+	if scannedLink.IsExpired(now, wrk.opts.CacheTTL, wrk.opts.CacheFailTTL) {
+		return false
+	}
+	linkq.status = scannedLink.ResponseCode
+	if scannedLink.Error != `` {
+		linkq.errScan = errors.New(scannedLink.Error)
+	}
+	return true
+}
+
+// toCache store the scanned external link into cache.
+func (wrk *worker) toCache(linkq linkQueue) {
+	if !linkq.isExternal || wrk.cache == nil {
+		return
+	}
+	var scannedLink = &jarink.ScannedLink{
+		Url:          linkq.url,
+		Size:         linkq.size,
+		ResponseCode: linkq.status,
+	}
+	if linkq.errScan != nil {
+		scannedLink.Error = linkq.errScan.Error()
+	}
+	var err = wrk.cache.Set(scannedLink)
+	if err != nil {
+		wrk.log.Warn(`set cache`, `url`, linkq.url, `error`, err.Error())
+	}
 }
 
 func (wrk *worker) seen(linkq linkQueue) {
@@ -299,41 +935,63 @@ func (wrk *worker) seen(linkq linkQueue) {
 		wrk.markBroken(linkq)
 		return
 	}
-	wrk.seenLink[linkq.url] = linkq.status
+	wrk.setSeen(linkq.url, linkq.status)
 }
 
 func (wrk *worker) markBroken(linkq linkQueue) {
 	var parentUrl = linkq.parentUrl.String()
-	var listBroken = wrk.result.BrokenLinks[parentUrl]
 	var brokenLink = Broken{
-		Link: linkq.url,
-		Code: linkq.status,
+		Link:    linkq.url,
+		Text:    linkq.text,
+		Element: elementOf(linkq.kind),
+		Code:    linkq.status,
+		Line:    linkq.line,
+		Column:  linkq.column,
+		Count:   linkq.count,
 	}
 	if linkq.errScan != nil {
 		brokenLink.Error = linkq.errScan.Error()
 	}
-	listBroken = append(listBroken, brokenLink)
-	wrk.result.BrokenLinks[parentUrl] = listBroken
+	if wrk.local != nil {
+		parentUrl = wrk.local.path(parentUrl)
+		brokenLink.Link = wrk.local.path(brokenLink.Link)
+	}
+	switch {
+	case wrk.opts.OnBroken == nil:
+		wrk.result.BrokenLinks[parentUrl] = append(
+			wrk.result.BrokenLinks[parentUrl], brokenLink)
+	case wrk.opts.baseline != nil &&
+		wrk.opts.baseline.suppress(wrk.result, internal.TimeNow(),
+			parentUrl, brokenLink):
+		// The broken link is accepted by baseline.
+	default:
+		wrk.opts.OnBroken(parentUrl, brokenLink)
+	}
 
-	wrk.seenLink[linkq.url] = linkq.status
+	wrk.setSeen(linkq.url, linkq.status)
 }
 
 // scan fetch the HTML page or image to check if its valid.
 func (wrk *worker) scan(linkq linkQueue) (resultq map[string]linkQueue) {
-	defer func() {
-		if wrk.opts.IsVerbose && linkq.errScan != nil {
-			wrk.log.Printf("error: %d %s error=%v\n", linkq.status,
-				linkq.url, linkq.errScan)
-		}
-		wrk.wg.Done()
-	}()
+	defer wrk.wg.Done()
+
+	wrk.log.Debug(`scan`, `url`, linkq.url, `parent`, linkq.parent())
+
+	var isPage = linkq.kind != atom.Img && !linkq.isExternal
+	var cachedPage *jarink.ScannedLink
+	if isPage && !wrk.isAudit() {
+		// The audit require the page content.
+		cachedPage = wrk.cachedPage(linkq.url)
+	}
 
 	resultq = make(map[string]linkQueue)
 	var (
 		httpResp *http.Response
 		err      error
 	)
-	httpResp, err = wrk.fetch(linkq)
+	var start = time.Now()
+	httpResp, err = wrk.fetch(linkq, cachedPage)
+	linkq.responseTime = time.Since(start)
 	if err != nil {
 		linkq.status = StatusBadLink
 		linkq.errScan = err
@@ -344,28 +1002,76 @@ func (wrk *worker) scan(linkq linkQueue) (resultq map[string]linkQueue) {
 
 	linkq.status = httpResp.StatusCode
 	linkq.size = httpResp.ContentLength
-	resultq[linkq.url] = linkq
+	linkq.contentType = httpResp.Header.Get(`Content-Type`)
+	if httpResp.Request.Response != nil {
+		// The Response in the last request is the redirect response
+		// that cause it.
+		linkq.redirectUrl = httpResp.Request.URL.String()
+		linkq.redirectStatus = httpResp.Request.Response.StatusCode
+	}
 
-	if slices.Contains(wrk.opts.ignoreStatus, httpResp.StatusCode) {
-		return nil
+	// The page has not been modified since the last scan, use the
+	// status, size, and links from the cache.
+	var isNotModified = cachedPage != nil &&
+		httpResp.StatusCode == http.StatusNotModified
+	if isNotModified {
+		linkq.status = cachedPage.ResponseCode
+		linkq.size = cachedPage.Size
+		if cachedPage.ContentType != `` {
+			linkq.contentType = cachedPage.ContentType
+		}
 	}
+	resultq[linkq.url] = linkq
 
-	if httpResp.StatusCode >= http.StatusBadRequest {
+	if slices.Contains(wrk.opts.ignoreStatus, linkq.status) {
 		return resultq
 	}
-	if linkq.kind == atom.Img || linkq.isExternal {
+	if linkq.status >= http.StatusBadRequest {
+		return resultq
+	}
+	if !isPage {
 		return resultq
 	}
 
-	var doc *html.Node
-	doc, _ = html.Parse(httpResp.Body)
-
-	// After we check the code and test for [html.Parse] there are
-	// no case actual cases where HTML content will return an error.
-	// The only possible error is when reading from body (io.Reader), and
-	// that is also almost impossible.
-	//
-	// [html.Parse]: https://go.googlesource.com/net/+/refs/tags/v0.40.0/html/parse.go#2347
+	var listLink []pageLink
+	if isNotModified {
+		listLink = fromPageLinks(cachedPage.Links)
+		linkq.meta = metaFromCache(cachedPage)
+	} else {
+		var content []byte
+		content, err = io.ReadAll(httpResp.Body)
+		if err != nil {
+			wrk.log.Warn(`scan`, `url`, linkq.url, `error`, err.Error())
+		}
+		var sum = sha256.Sum256(content)
+		var contentHash = hex.EncodeToString(sum[:])
+		if cachedPage != nil && cachedPage.ContentHash == contentHash {
+			listLink = fromPageLinks(cachedPage.Links)
+			linkq.meta = metaFromCache(cachedPage)
+		} else {
+			listLink, linkq.meta = extractLinks(content)
+		}
+		if wrk.opts.Seo {
+			// The title, number of "h1", and text of the page are
+			// only needed by SEO audit.
+			linkq.meta = extractMeta(content)
+		}
+		linkq.meta.parseHeader(httpResp.Header)
+		if wrk.opts.A11y {
+			linkq.issues = auditA11y(content)
+		}
+		if wrk.httpsChecker != nil {
+			linkq.issues = append(linkq.issues,
+				wrk.httpsChecker.audit(wrk.ctx, content)...)
+		}
+		if wrk.opts.Headers {
+			var isHttps = strings.HasPrefix(linkq.url, `https://`)
+			linkq.issues = append(linkq.issues,
+				auditHeaders(httpResp.Header, isHttps)...)
+		}
+		wrk.storePage(linkq, httpResp.Header, contentHash, listLink)
+	}
+	resultq[linkq.url] = linkq
 
 	var scanUrl *url.URL
 
@@ -374,74 +1080,201 @@ func (wrk *worker) scan(linkq linkQueue) (resultq map[string]linkQueue) {
 		log.Fatal(err)
 	}
 
-	var node *html.Node
-	for node = range doc.Descendants() {
-		if node.Type != html.ElementNode {
-			continue
-		}
-		var nodeLink *linkQueue
-		if node.DataAtom == atom.A {
-			for _, attr := range node.Attr {
-				if attr.Key != `href` {
-					continue
-				}
-				nodeLink = wrk.processLink(scanUrl, attr.Val, atom.A)
-				break
-			}
-		} else if node.DataAtom == atom.Img {
-			for _, attr := range node.Attr {
-				if attr.Key != `src` {
-					continue
-				}
-				nodeLink = wrk.processLink(scanUrl, attr.Val, atom.Img)
-				break
-			}
-		} else {
+	for _, plink := range listLink {
+		if plink.inCode && wrk.opts.SkipCode {
 			continue
 		}
+		var nodeLink = wrk.processLink(scanUrl, plink.value, plink.kind)
 		if nodeLink == nil {
 			continue
 		}
-		_, seen := resultq[nodeLink.url]
-		if !seen {
-			wrk.checkExternal(nodeLink)
-			resultq[nodeLink.url] = *nodeLink
+		if plink.inNav && wrk.opts.Seo {
+			linkq.meta.listNav = append(linkq.meta.listNav,
+				nodeLink.url)
 		}
+		var prevLink, seen = resultq[nodeLink.url]
+		if seen {
+			if prevLink.status == 0 || prevLink.errScan != nil {
+				prevLink.count++
+				resultq[nodeLink.url] = prevLink
+			}
+			continue
+		}
+		nodeLink.text = plink.text
+		nodeLink.line = plink.line
+		nodeLink.column = plink.column
+		nodeLink.count = 1
+		wrk.checkExternal(nodeLink)
+		resultq[nodeLink.url] = *nodeLink
+	}
+	if wrk.opts.Seo {
+		wrk.queueCanonical(scanUrl, &linkq, resultq)
+		resultq[linkq.url] = linkq
 	}
 	return resultq
 }
 
-func (wrk *worker) fetch(linkq linkQueue) (
+// queueCanonical resolve the canonical link of page into
+// [pageMeta.canonicalUrl] and add it into resultq, so its status can be
+// checked by SEO audit.
+// When scanning local directory, only the path of canonical link is
+// used, since the canonical link may use the URL of website.
+func (wrk *worker) queueCanonical(
+	scanUrl *url.URL, linkq *linkQueue, resultq map[string]linkQueue,
+) {
+	var canonical = linkq.meta.canonical
+	if canonical == `` {
+		return
+	}
+	if wrk.local != nil {
+		var canonicalUrl, err = url.Parse(canonical)
+		if err == nil && canonicalUrl.IsAbs() {
+			canonical = canonicalUrl.Path
+			if canonical == `` {
+				canonical = `/`
+			}
+		}
+	}
+	var nodeLink = wrk.processLink(scanUrl, canonical, atom.Link)
+	if nodeLink == nil {
+		return
+	}
+	linkq.meta.canonicalUrl = nodeLink.url
+	var _, seen = resultq[nodeLink.url]
+	if seen {
+		return
+	}
+	nodeLink.count = 1
+	wrk.checkExternal(nodeLink)
+	resultq[nodeLink.url] = *nodeLink
+}
+
+// isAudit return true if one of the audit that require the page content
+// is enabled.
+func (wrk *worker) isAudit() bool {
+	return wrk.opts.Seo || wrk.opts.A11y || wrk.httpsChecker != nil ||
+		wrk.opts.Headers
+}
+
+// cachedPage return the internal page from the cache, for sending
+// conditional request.
+// It return nil if the cache is disabled, refreshed, the page is in local
+// directory, or the page has not been scanned before.
+func (wrk *worker) cachedPage(pageUrl string) (scannedLink *jarink.ScannedLink) {
+	if wrk.cache == nil || wrk.opts.RefreshCache || wrk.local != nil {
+		return nil
+	}
+	var err error
+	scannedLink, err = wrk.cache.Get(pageUrl)
+	if err != nil {
+		wrk.log.Warn(`get cache`, `url`, pageUrl, `error`, err.Error())
+		return nil
+	}
+	if scannedLink == nil || scannedLink.ContentHash == `` {
+		// The link is not scanned as internal page before.
+		return nil
+	}
+	return scannedLink
+}
+
+// storePage store the internal page, its validators, content hash, and
+// list of links inside it, into the cache.
+// The page without header "ETag" and "Last-Modified" is not stored, since
+// it cannot be requested using conditional request, so the cache does not
+// grow with every pages in the website.
+func (wrk *worker) storePage(
+	linkq linkQueue, header http.Header, contentHash string,
+	listLink []pageLink,
+) {
+	if wrk.cache == nil || wrk.local != nil {
+		// The page in local directory is served from random port,
+		// so its not cached.
+		return
+	}
+	var (
+		etag         = header.Get(`ETag`)
+		lastModified = header.Get(`Last-Modified`)
+	)
+	if etag == `` && lastModified == `` {
+		return
+	}
+	var scannedLink = &jarink.ScannedLink{
+		Url:          linkq.url,
+		ETag:         etag,
+		LastModified: lastModified,
+		ContentHash:  contentHash,
+		ContentType:  linkq.contentType,
+		Canonical:    linkq.meta.canonical,
+		Links:        toPageLinks(listLink),
+		Size:         linkq.size,
+		ResponseCode: linkq.status,
+		NoIndex:      linkq.meta.noIndex,
+	}
+	var err = wrk.cache.Set(scannedLink)
+	if err != nil {
+		wrk.log.Warn(`set cache`, `url`, linkq.url, `error`, err.Error())
+	}
+}
+
+// fetch the link using HTTP GET, or HEAD for image.
+// If cachedPage is not nil, the request is send with header
+// "If-None-Match" and "If-Modified-Since" using its ETag and
+// LastModified.
+func (wrk *worker) fetch(linkq linkQueue, cachedPage *jarink.ScannedLink) (
 	httpResp *http.Response,
 	err error,
 ) {
 	const maxRetry = 5
-	var retry int
-	for retry < 5 {
-		if linkq.kind == atom.Img {
-			if wrk.opts.IsVerbose {
-				wrk.log.Printf("fetch: HEAD %s", linkq.url)
+	var (
+		method  = http.MethodGet
+		attempt int
+	)
+	if linkq.kind == atom.Img {
+		method = http.MethodHead
+	}
+	for attempt < maxRetry {
+		attempt++
+
+		var httpReq *http.Request
+		httpReq, err = http.NewRequestWithContext(wrk.ctx, method,
+			linkq.url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if cachedPage != nil {
+			if cachedPage.ETag != `` {
+				httpReq.Header.Set(`If-None-Match`, cachedPage.ETag)
 			}
-			httpResp, err = wrk.httpc.Head(linkq.url)
-		} else {
-			if wrk.opts.IsVerbose {
-				wrk.log.Printf("fetch: GET %s", linkq.url)
+			if cachedPage.LastModified != `` {
+				httpReq.Header.Set(`If-Modified-Since`,
+					cachedPage.LastModified)
 			}
-			httpResp, err = wrk.httpc.Get(linkq.url)
+		}
+
+		var start = time.Now()
+		httpResp, err = wrk.httpc.Do(httpReq)
+		var attrs = []any{
+			slog.String(`method`, method),
+			slog.String(`url`, linkq.url),
+			slog.String(`parent`, linkq.parent()),
+			slog.Duration(`duration`, time.Since(start)),
+			slog.Int(`attempt`, attempt),
 		}
 		if err == nil {
+			attrs = append(attrs, slog.Int(`status`, httpResp.StatusCode))
+			wrk.log.Info(`fetch`, attrs...)
 			return httpResp, nil
 		}
+		attrs = append(attrs, slog.Int(`status`, StatusBadLink),
+			slog.String(`error`, err.Error()))
+
 		var errDNS *net.DNSError
-		if !errors.As(err, &errDNS) {
-			return nil, err
+		if !errors.As(err, &errDNS) || !errDNS.Timeout() {
+			wrk.log.Info(`fetch`, attrs...)
+			break
 		}
-		if errDNS.Timeout() {
-			retry++
-			wrk.log.Printf(`fetch %s: %s (%d/%d)`, linkq.url, err, retry, maxRetry)
-			continue
-		}
-		break
+		wrk.log.Warn(`fetch retry`,
+			append(attrs, slog.Int(`max_attempt`, maxRetry))...)
 	}
 	return nil, err
 }
@@ -479,10 +1312,15 @@ func (wrk *worker) processLink(parentUrl *url.URL, val string, kind atom.Atom) (
 			kind:      kind,
 		}
 	}
-	if val[0] == '/' {
+	switch {
+	case val[0] == '/':
 		// val is absolute to parent URL.
 		newUrl = wrk.baseUrl.JoinPath(newUrl.Path)
-	} else {
+	case wrk.local != nil && path.Ext(parentUrl.Path) != ``:
+		// val is relative to the directory of file in local
+		// directory.
+		newUrl = parentUrl.ResolveReference(&url.URL{Path: newUrl.Path})
+	default:
 		// val is relative to parent URL.
 		newUrl = parentUrl.JoinPath(`/`, newUrl.Path)
 	}
@@ -491,6 +1329,9 @@ func (wrk *worker) processLink(parentUrl *url.URL, val string, kind atom.Atom) (
 		url:       strings.TrimSuffix(newUrl.String(), `/`),
 		kind:      kind,
 	}
+	if wrk.local != nil {
+		linkq.url = wrk.local.canonical(linkq.url)
+	}
 	return linkq
 }
 
@@ -504,6 +1345,9 @@ func (wrk *worker) pushResult(resultq map[string]linkQueue) {
 		case wrk.resultq <- resultq:
 			tick.Stop()
 			return
+		case <-wrk.ctx.Done():
+			tick.Stop()
+			return
 		case <-tick.C:
 		}
 	}
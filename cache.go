@@ -6,43 +6,156 @@ package jarink
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
 	"sync"
+	"time"
 
 	"git.sr.ht/~shulhan/jarink/internal"
 )
 
 // ScannedLink store information about the link.
 type ScannedLink struct {
-	Url          string `json:"url"`
-	Size         int64  `json:"size"`
-	ResponseCode int    `json:"response_code"`
+	// CheckedAt the time when the link is scanned.
+	CheckedAt time.Time `json:"checked_at"`
+
+	Url string `json:"url"`
+
+	// Error the error message when scanning the link, if any.
+	Error string `json:"error,omitempty"`
+
+	// ETag and LastModified the value of HTTP response header "ETag"
+	// and "Last-Modified", used for conditional request when scanning
+	// the same page again.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// ContentHash the hex encoded SHA-256 of page content.
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// ContentType the value of HTTP response header "Content-Type".
+	ContentType string `json:"content_type,omitempty"`
+
+	// Canonical the href of element "link" with rel "canonical" in
+	// the page.
+	Canonical string `json:"canonical,omitempty"`
+
+	// Links contains the links found inside the page.
+	// It only set on the page that has been parsed.
+	Links []PageLink `json:"links,omitempty"`
+
+	Size         int64 `json:"size"`
+	ResponseCode int   `json:"response_code"`
+
+	// NoIndex true if the page should not be indexed by search
+	// engine, set by element "meta" with name "robots" or by HTTP
+	// response header "X-Robots-Tag".
+	NoIndex bool `json:"no_index,omitempty"`
+}
+
+// PageLink contains the link found inside the page.
+type PageLink struct {
+	// Value of the link, as written in the page.
+	Value string `json:"value"`
+
+	// Text of the anchor or the alternate text of image.
+	Text string `json:"text,omitempty"`
+
+	// Element and attribute where the link found, for example "a@href".
+	Element string `json:"element"`
+
+	// Line and Column of the element in the page, start from 1.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+
+	// InCode true if the link is inside the "code" or "pre" element.
+	InCode bool `json:"in_code,omitempty"`
+}
+
+// IsFailed return true if the link is scanned with error or the response
+// code is 400 or greater.
+func (scannedLink *ScannedLink) IsFailed() bool {
+	return scannedLink.Error != `` ||
+		scannedLink.ResponseCode >= http.StatusBadRequest
+}
+
+// IsExpired return true if the link has been scanned longer than ttl, or
+// failTTL if the link [IsFailed], from time now.
+// The link that does not have CheckedAt is always expired.
+func (scannedLink *ScannedLink) IsExpired(
+	now time.Time, ttl, failTTL time.Duration,
+) bool {
+	if scannedLink.CheckedAt.IsZero() {
+		return true
+	}
+	if scannedLink.IsFailed() {
+		ttl = failTTL
+	}
+	return now.Sub(scannedLink.CheckedAt) >= ttl
 }
 
 // Cache store external links that has been scanned, to minize
 // request to the same URL in the future.
-// The cache is stored as JSON file under user's cache directory, inside
-// "jarink" directory.
+// Cache implement [CacheStore] using JSON file, where all of the links are
+// loaded into memory.
+// The default cache, loaded using [LoadCache], is stored under user's
+// cache directory, inside "jarink" directory.
 // For example, in Linux it should be "$HOME/.cache/jarink/cache.json".
 // See [os.UserCacheDir] for location specific to operating system.
+//
+// Saving the cache is safe from concurrent jarink processes and crash.
+// The cache file is locked during save, merged with the links in the cache
+// file that has been saved by other process, and then written into
+// temporary file before renamed into cache file.
 type Cache struct {
 	ScannedLinks map[string]*ScannedLink `json:"scanned_links"`
-	file         string
-	mtx          sync.Mutex
+
+	// deleted contains the URL of link that has been deleted since the
+	// last save and the time when its deleted, to prevent the link
+	// restored when merging with the cache file.
+	deleted map[string]time.Time
+
+	file string
+	mtx  sync.Mutex
 }
 
 // LoadCache from local storage.
 func LoadCache() (cache *Cache, err error) {
 	var logp = `LoadCache`
+	var file string
 
-	cache = &Cache{
-		ScannedLinks: map[string]*ScannedLink{},
+	file, err = internal.CacheFile()
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	cache, err = openCache(file)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
 	}
+	return cache, nil
+}
 
-	cache.file, err = internal.CacheFile()
+// OpenJSONCache open the cache from JSON file.
+// If the file does not exist, it will be created on [Cache.Save].
+func OpenJSONCache(file string) (cache *Cache, err error) {
+	var logp = `OpenJSONCache`
+
+	cache, err = openCache(file)
 	if err != nil {
 		return nil, fmt.Errorf(`%s: %w`, logp, err)
 	}
+	return cache, nil
+}
+
+func openCache(file string) (cache *Cache, err error) {
+	cache = &Cache{
+		ScannedLinks: map[string]*ScannedLink{},
+		deleted:      map[string]time.Time{},
+		file:         file,
+	}
 
 	var cacheJson []byte
 	cacheJson, err = os.ReadFile(cache.file)
@@ -50,28 +163,86 @@ func LoadCache() (cache *Cache, err error) {
 		if os.IsNotExist(err) {
 			return cache, nil
 		}
-		return nil, fmt.Errorf(`%s: %w`, logp, err)
+		return nil, err
 	}
 
 	err = json.Unmarshal(cacheJson, &cache)
 	if err != nil {
-		return nil, fmt.Errorf(`%s: %w`, logp, err)
+		return nil, err
 	}
-
 	return cache, nil
 }
 
+// Close the cache.
+// It does nothing, the cache file is only written on [Cache.Save].
+func (cache *Cache) Close() error {
+	return nil
+}
+
+// Delete the scanned link by url.
+func (cache *Cache) Delete(url string) error {
+	cache.mtx.Lock()
+	delete(cache.ScannedLinks, url)
+	if cache.deleted == nil {
+		cache.deleted = map[string]time.Time{}
+	}
+	cache.deleted[url] = internal.TimeNow().UTC()
+	cache.mtx.Unlock()
+	return nil
+}
+
+// Iterate call the function fn for each scanned link in the cache,
+// sorted by its URL, until fn return false.
+// The fn must not call other methods of cache.
+func (cache *Cache) Iterate(fn func(scannedLink *ScannedLink) bool) error {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	var listUrl = slices.Sorted(maps.Keys(cache.ScannedLinks))
+	for _, url := range listUrl {
+		if !fn(cache.ScannedLinks[url]) {
+			break
+		}
+	}
+	return nil
+}
+
 // Get return the scanned link information by url.
-func (cache *Cache) Get(url string) (scannedLink *ScannedLink) {
+// It return nil if the url does not exist in the cache.
+func (cache *Cache) Get(url string) (scannedLink *ScannedLink, err error) {
 	cache.mtx.Lock()
 	scannedLink = cache.ScannedLinks[url]
 	cache.mtx.Unlock()
-	return scannedLink
+	return scannedLink, nil
 }
 
 // Save the cache into local storage.
+// The links in the cache file that are not exist in the cache, or scanned
+// later than the one in the cache, are merged into the cache before
+// saving.
 func (cache *Cache) Save() (err error) {
 	var logp = `Save`
+
+	var unlock func() error
+	unlock, err = lockFile(cache.file + `.lock`)
+	if err != nil {
+		return fmt.Errorf(`%s: %w`, logp, err)
+	}
+	defer func() {
+		var errUnlock = unlock()
+		if err == nil && errUnlock != nil {
+			err = fmt.Errorf(`%s: %w`, logp, errUnlock)
+		}
+	}()
+
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	err = cache.merge()
+	if err != nil {
+		return fmt.Errorf(`%s: %w`, logp, err)
+	}
+
 	var cacheJson []byte
 	cacheJson, err = json.MarshalIndent(cache, ``, `  `)
 	if err != nil {
@@ -80,25 +251,64 @@ func (cache *Cache) Save() (err error) {
 
 	cacheJson = append(cacheJson, '\n')
 
-	err = os.WriteFile(cache.file, cacheJson, 0600)
+	err = internal.WriteFileAtomic(cache.file, cacheJson, 0600)
 	if err != nil {
 		return fmt.Errorf(`%s: %w`, logp, err)
 	}
+	clear(cache.deleted)
 	return nil
 }
 
-func (cache *Cache) Set(url string, respCode int, size int64) {
-	cache.mtx.Lock()
-	defer cache.mtx.Unlock()
+// merge the links from the cache file into the cache.
+// The link in the file is merged if its not exist in the cache and not
+// deleted, or scanned later than the one in the cache or deleted.
+func (cache *Cache) merge() (err error) {
+	var cacheJson []byte
+	cacheJson, err = os.ReadFile(cache.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
 
-	var scannedLink = cache.ScannedLinks[url]
-	if scannedLink != nil {
-		return
+	var saved Cache
+	err = json.Unmarshal(cacheJson, &saved)
+	if err != nil {
+		// The cache file is corrupted, replace it with the
+		// current cache.
+		return nil
 	}
-	scannedLink = &ScannedLink{
-		Url:          url,
-		Size:         size,
-		ResponseCode: respCode,
+
+	for url, scannedLink := range saved.ScannedLinks {
+		if scannedLink == nil {
+			continue
+		}
+		var current = cache.ScannedLinks[url]
+		if current != nil {
+			if scannedLink.CheckedAt.After(current.CheckedAt) {
+				cache.ScannedLinks[url] = scannedLink
+			}
+			continue
+		}
+		var deletedAt, isDeleted = cache.deleted[url]
+		if isDeleted && !scannedLink.CheckedAt.After(deletedAt) {
+			continue
+		}
+		cache.ScannedLinks[url] = scannedLink
 	}
-	cache.ScannedLinks[url] = scannedLink
+	return nil
+}
+
+// Set store the scanned link into cache, replacing the existing one with
+// the same URL.
+// If the CheckedAt is zero, it will be set to current time.
+func (cache *Cache) Set(scannedLink *ScannedLink) error {
+	if scannedLink.CheckedAt.IsZero() {
+		scannedLink.CheckedAt = internal.TimeNow().UTC()
+	}
+	cache.mtx.Lock()
+	cache.ScannedLinks[scannedLink.Url] = scannedLink
+	cache.mtx.Unlock()
+	return nil
 }
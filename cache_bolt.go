@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package jarink
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// boltBucket the name of bucket where the scanned links stored.
+var boltBucket = []byte(`scanned_links`)
+
+// boltOpenTimeout the maximum duration to wait the database file locked
+// by other process.
+const boltOpenTimeout = 30 * time.Second
+
+// BoltCache implement [CacheStore] that store the scanned links in the
+// embedded key-value database using [bbolt].
+// Unlike [Cache], the links are not loaded into memory, so its suitable for
+// cache with millions of links.
+//
+// The database file is locked while its opened, other process that open
+// the same file will wait until the file is closed.
+//
+// [bbolt]: https://github.com/etcd-io/bbolt
+type BoltCache struct {
+	db *bolt.DB
+}
+
+// OpenBoltCache open or create the cache database in file.
+func OpenBoltCache(file string) (cache *BoltCache, err error) {
+	var logp = `OpenBoltCache`
+	var opts = &bolt.Options{
+		Timeout: boltOpenTimeout,
+	}
+
+	cache = &BoltCache{}
+	cache.db, err = bolt.Open(file, 0600, opts)
+	if err != nil {
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+
+	err = cache.db.Update(func(tx *bolt.Tx) error {
+		var _, errCreate = tx.CreateBucketIfNotExists(boltBucket)
+		return errCreate
+	})
+	if err != nil {
+		_ = cache.db.Close()
+		return nil, fmt.Errorf(`%s: %w`, logp, err)
+	}
+	return cache, nil
+}
+
+// Close the database.
+func (cache *BoltCache) Close() (err error) {
+	err = cache.db.Close()
+	if err != nil {
+		return fmt.Errorf(`Close: %w`, err)
+	}
+	return nil
+}
+
+// Delete the scanned link by url.
+func (cache *BoltCache) Delete(url string) (err error) {
+	err = cache.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(url))
+	})
+	if err != nil {
+		return fmt.Errorf(`Delete: %w`, err)
+	}
+	return nil
+}
+
+// Get return the scanned link by url.
+// It return nil if the url does not exist in the cache.
+func (cache *BoltCache) Get(url string) (scannedLink *ScannedLink, err error) {
+	err = cache.db.View(func(tx *bolt.Tx) error {
+		var val = tx.Bucket(boltBucket).Get([]byte(url))
+		if val == nil {
+			return nil
+		}
+		scannedLink = &ScannedLink{}
+		return json.Unmarshal(val, scannedLink)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`Get %s: %w`, url, err)
+	}
+	return scannedLink, nil
+}
+
+// Iterate call the function fn for each scanned link in the cache,
+// sorted by its URL, until fn return false.
+// The fn must not call other methods of cache.
+func (cache *BoltCache) Iterate(fn func(scannedLink *ScannedLink) bool) (
+	err error,
+) {
+	err = cache.db.View(func(tx *bolt.Tx) error {
+		var cursor = tx.Bucket(boltBucket).Cursor()
+		var key, val = cursor.First()
+		for ; key != nil; key, val = cursor.Next() {
+			var scannedLink = &ScannedLink{}
+			var errJson = json.Unmarshal(val, scannedLink)
+			if errJson != nil {
+				return fmt.Errorf(`%s: %w`, key, errJson)
+			}
+			if !fn(scannedLink) {
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf(`Iterate: %w`, err)
+	}
+	return nil
+}
+
+// Save flush the database into storage.
+// The database is already written on each [BoltCache.Set] and
+// [BoltCache.Delete], so this method only make sure the data is synced.
+func (cache *BoltCache) Save() (err error) {
+	err = cache.db.Sync()
+	if err != nil {
+		return fmt.Errorf(`Save: %w`, err)
+	}
+	return nil
+}
+
+// Set store the scanned link into cache, replacing the existing one with
+// the same URL.
+// If the CheckedAt is zero, it will be set to current time.
+func (cache *BoltCache) Set(scannedLink *ScannedLink) (err error) {
+	if scannedLink.CheckedAt.IsZero() {
+		scannedLink.CheckedAt = internal.TimeNow().UTC()
+	}
+
+	var val []byte
+	val, err = json.Marshal(scannedLink)
+	if err != nil {
+		return fmt.Errorf(`Set: %w`, err)
+	}
+
+	err = cache.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(scannedLink.Url), val)
+	})
+	if err != nil {
+		return fmt.Errorf(`Set: %w`, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !unix
+
+package jarink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockStaleAge the age of lock file where its assumed left by crashed
+// process.
+const lockStaleAge = time.Minute
+
+// lockFile acquire the exclusive lock by creating the file, and wait until
+// the file is removed by other process.
+// It return the function to release the lock.
+func lockFile(file string) (unlock func() error, err error) {
+	var deadline = time.Now().Add(2 * lockStaleAge)
+	for {
+		var lock *os.File
+		lock, err = os.OpenFile(file,
+			os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_ = lock.Close()
+			unlock = func() error {
+				return os.Remove(file)
+			}
+			return unlock, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		var fi os.FileInfo
+		fi, err = os.Stat(file)
+		if err == nil && time.Since(fi.ModTime()) > lockStaleAge {
+			_ = os.Remove(file)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf(`lockFile %s: timeout`, file)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build unix
+
+package jarink
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile acquire the exclusive lock on the file, create it if its not
+// exist, and wait until the lock is released by other process.
+// It return the function to release the lock.
+func lockFile(file string) (unlock func() error, err error) {
+	var lock *os.File
+	lock, err = os.OpenFile(file, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		_ = lock.Close()
+		return nil, err
+	}
+
+	unlock = func() error {
+		var errUnlock = syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+		var errClose = lock.Close()
+		if errUnlock != nil {
+			return errUnlock
+		}
+		return errClose
+	}
+	return unlock, nil
+}
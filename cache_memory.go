@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package jarink
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// MemoryCache implement [CacheStore] that store the scanned links in
+// memory only.
+// Its mostly used for testing.
+type MemoryCache struct {
+	links map[string]*ScannedLink
+	mtx   sync.Mutex
+}
+
+// NewMemoryCache create new, empty MemoryCache.
+func NewMemoryCache() (cache *MemoryCache) {
+	return &MemoryCache{
+		links: map[string]*ScannedLink{},
+	}
+}
+
+// Close the cache.
+// It does nothing.
+func (cache *MemoryCache) Close() error {
+	return nil
+}
+
+// Delete the scanned link by url.
+func (cache *MemoryCache) Delete(url string) error {
+	cache.mtx.Lock()
+	delete(cache.links, url)
+	cache.mtx.Unlock()
+	return nil
+}
+
+// Get return the scanned link by url.
+// It return nil if the url does not exist in the cache.
+func (cache *MemoryCache) Get(url string) (scannedLink *ScannedLink, err error) {
+	cache.mtx.Lock()
+	scannedLink = cache.links[url]
+	cache.mtx.Unlock()
+	return scannedLink, nil
+}
+
+// Iterate call the function fn for each scanned link in the cache,
+// sorted by its URL, until fn return false.
+// The fn must not call other methods of cache.
+func (cache *MemoryCache) Iterate(fn func(scannedLink *ScannedLink) bool) error {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	var listUrl = slices.Sorted(maps.Keys(cache.links))
+	for _, url := range listUrl {
+		if !fn(cache.links[url]) {
+			break
+		}
+	}
+	return nil
+}
+
+// Save the cache.
+// It does nothing.
+func (cache *MemoryCache) Save() error {
+	return nil
+}
+
+// Set store the scanned link into cache, replacing the existing one with
+// the same URL.
+// If the CheckedAt is zero, it will be set to current time.
+func (cache *MemoryCache) Set(scannedLink *ScannedLink) error {
+	if scannedLink.CheckedAt.IsZero() {
+		scannedLink.CheckedAt = internal.TimeNow().UTC()
+	}
+	cache.mtx.Lock()
+	cache.links[scannedLink.Url] = scannedLink
+	cache.mtx.Unlock()
+	return nil
+}
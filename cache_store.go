@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package jarink
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CacheStore define the interface for storage of scanned links.
+//
+// There are three implementations of CacheStore: [Cache] that store the
+// links in JSON file, [BoltCache] that store the links in embedded
+// key-value database, and [MemoryCache] that store the links in memory
+// only.
+type CacheStore interface {
+	// Get return the scanned link by url.
+	// It return nil if the url does not exist in the store.
+	Get(url string) (*ScannedLink, error)
+
+	// Set store the scanned link, replacing the existing one with the
+	// same URL.
+	// If the CheckedAt is zero, it will be set to current time.
+	Set(scannedLink *ScannedLink) error
+
+	// Delete the scanned link by url.
+	Delete(url string) error
+
+	// Iterate call the function fn for each scanned link in the store,
+	// sorted by its URL, until fn return false.
+	// The fn must not call other methods of store.
+	Iterate(fn func(scannedLink *ScannedLink) bool) error
+
+	// Save persist the scanned links into storage.
+	Save() error
+
+	// Close release the resources used by store.
+	Close() error
+}
+
+// OpenCache open the CacheStore based on the file extension.
+// If the file is empty, it will open the default cache using
+// [LoadCache].
+// If the file extension is ".db", ".bolt", or ".bbolt", it will open the
+// [BoltCache].
+// Otherwise, it will open the file as JSON using [OpenJSONCache].
+func OpenCache(file string) (store CacheStore, err error) {
+	if file == `` {
+		return LoadCache()
+	}
+	var ext = strings.ToLower(filepath.Ext(file))
+	switch ext {
+	case `.db`, `.bolt`, `.bbolt`:
+		store, err = OpenBoltCache(file)
+	default:
+		store, err = OpenJSONCache(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`OpenCache: %w`, err)
+	}
+	return store, nil
+}
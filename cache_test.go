@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package jarink
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+func TestCacheStore(t *testing.T) {
+	var dir = t.TempDir()
+
+	type testCase struct {
+		open func() (CacheStore, error)
+		name string
+	}
+	var listCase = []testCase{{
+		name: `Cache`,
+		open: func() (CacheStore, error) {
+			return OpenJSONCache(filepath.Join(dir, `cache.json`))
+		},
+	}, {
+		name: `BoltCache`,
+		open: func() (CacheStore, error) {
+			return OpenBoltCache(filepath.Join(dir, `cache.db`))
+		},
+	}, {
+		name: `MemoryCache`,
+		open: func() (CacheStore, error) {
+			return NewMemoryCache(), nil
+		},
+	}}
+	for _, tcase := range listCase {
+		t.Run(tcase.name, func(t *testing.T) {
+			var store, err = tcase.open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			testCacheStore(t, store)
+		})
+	}
+}
+
+func testCacheStore(t *testing.T, store CacheStore) {
+	var checkedAt = time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	var err error
+	for _, url := range []string{`https://c`, `https://a`, `https://b`} {
+		err = store.Set(&ScannedLink{
+			CheckedAt:    checkedAt,
+			Url:          url,
+			ResponseCode: 200,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = store.Delete(`https://b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scannedLink *ScannedLink
+	scannedLink, err = store.Get(`https://a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exp = &ScannedLink{
+		CheckedAt:    checkedAt,
+		Url:          `https://a`,
+		ResponseCode: 200,
+	}
+	test.Assert(t, `Get`, exp, scannedLink)
+
+	scannedLink, err = store.Get(`https://b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `Get deleted`, (*ScannedLink)(nil), scannedLink)
+
+	var got []string
+	err = store.Iterate(func(scannedLink *ScannedLink) bool {
+		got = append(got, scannedLink.Url)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `Iterate`, []string{`https://a`, `https://c`}, got)
+
+	got = nil
+	err = store.Iterate(func(scannedLink *ScannedLink) bool {
+		got = append(got, scannedLink.Url)
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.Assert(t, `Iterate stop`, []string{`https://a`}, got)
+
+	err = store.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScannedLink_IsExpired(t *testing.T) {
+	var now = time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		desc        string
+		scannedLink ScannedLink
+		exp         bool
+	}
+	var listCase = []testCase{{
+		desc: `Without CheckedAt`,
+		scannedLink: ScannedLink{
+			ResponseCode: 200,
+		},
+		exp: true,
+	}, {
+		desc: `Success not expired`,
+		scannedLink: ScannedLink{
+			CheckedAt:    now.Add(-2 * time.Hour),
+			ResponseCode: 200,
+		},
+	}, {
+		desc: `Failed expired`,
+		scannedLink: ScannedLink{
+			CheckedAt:    now.Add(-2 * time.Hour),
+			ResponseCode: 404,
+		},
+		exp: true,
+	}, {
+		desc: `Error not expired`,
+		scannedLink: ScannedLink{
+			CheckedAt: now.Add(-30 * time.Minute),
+			Error:     `no such host`,
+		},
+	}}
+	for _, tcase := range listCase {
+		var got = tcase.scannedLink.IsExpired(now, 24*time.Hour, time.Hour)
+		test.Assert(t, tcase.desc, tcase.exp, got)
+	}
+}
+
+// TestCache_Save test saving two caches loaded from the same file, like
+// two jarink processes running in parallel.
+func TestCache_Save(t *testing.T) {
+	var orgCacheFile = internal.CacheFile
+	var cacheFile = filepath.Join(t.TempDir(), `cache.json`)
+	t.Cleanup(func() {
+		internal.CacheFile = orgCacheFile
+	})
+	internal.CacheFile = func() (string, error) {
+		return cacheFile, nil
+	}
+
+	var checkedAt = time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	var newScannedLink = func(url string, code int) *ScannedLink {
+		return &ScannedLink{
+			CheckedAt:    checkedAt,
+			Url:          url,
+			ResponseCode: code,
+		}
+	}
+
+	var cache, err = LoadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Set(newScannedLink(`https://a`, 200))
+	cache.Set(newScannedLink(`https://b`, 200))
+	err = cache.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cache1, cache2 *Cache
+	cache1, err = LoadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache2, err = LoadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkedAt = checkedAt.Add(time.Hour)
+	cache1.Set(newScannedLink(`https://b`, 404))
+	cache1.Set(newScannedLink(`https://c`, 200))
+	cache2.Set(newScannedLink(`https://d`, 200))
+	cache2.Delete(`https://a`)
+
+	err = cache1.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cache2.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err = LoadCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got = map[string]int{}
+	cache.Iterate(func(scannedLink *ScannedLink) bool {
+		got[scannedLink.Url] = scannedLink.ResponseCode
+		return true
+	})
+	var exp = map[string]int{
+		`https://b`: 404,
+		`https://c`: 200,
+		`https://d`: 200,
+	}
+	test.Assert(t, `Save`, exp, got)
+}
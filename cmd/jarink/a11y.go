@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdA11y the "a11y" command.
+type cmdA11y struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+}
+
+func newCmdA11y() (cmd *cmdA11y) {
+	cmd = &cmdA11y{}
+	cmd.flagSet = newFlagSet(`a11y`, `[OPTIONS] <URL | DIRECTORY>`,
+		`Scan the website or local directory and print the accessibility`+
+			` issues on each HTML page.`)
+
+	cmd.crawl.register(cmd.flagSet, &cmd.opts)
+
+	return cmd
+}
+
+func (cmd *cmdA11y) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and print the accessibility
+// issues on each page as JSON to standard output.
+// On SIGINT, the scan is stopped and the issues on pages that has been
+// scanned are printed.
+func (cmd *cmdA11y) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+	opts.A11y = true
+
+	var (
+		result        *brokenlinks.Result
+		isInterrupted bool
+	)
+	result, isInterrupted, err = cmd.crawl.scan(opts)
+	if err != nil {
+		return err
+	}
+
+	err = printIssues(result.Issues)
+	if err != nil {
+		return err
+	}
+
+	if isInterrupted {
+		log.Printf(`Scan interrupted.`)
+		os.Exit(1)
+	}
+	return nil
+}
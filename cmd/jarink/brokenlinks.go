@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdBrokenlinks the "brokenlinks" command.
+type cmdBrokenlinks struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	stream bool
+}
+
+func newCmdBrokenlinks() (cmd *cmdBrokenlinks) {
+	cmd = &cmdBrokenlinks{}
+	cmd.flagSet = newFlagSet(`brokenlinks`,
+		`[OPTIONS] <URL | DIRECTORY>`,
+		`Scan the website or local directory for broken links.`)
+
+	var (
+		flagSet = cmd.flagSet
+		opts    = &cmd.opts
+	)
+
+	flagSet.StringVar(&opts.BaselineFile, `baseline`, ``,
+		`JSON file that contains list of accepted broken links.`)
+
+	cmd.crawl.register(flagSet, opts)
+
+	flagSet.BoolVar(&opts.MixedContent, `mixed-content`, false,
+		`Report the subresources loaded using "http" on "https" website.`)
+
+	flagSet.StringVar(&opts.PastResultFile, `past-result`, ``,
+		`Scan only pages with broken links from the past JSON result.`)
+
+	flagSet.BoolVar(&opts.Resume, `resume`, false,
+		`Continue the scan from the state in directory set by "-state".`)
+
+	flagSet.BoolVar(&cmd.stream, `stream`, false,
+		`Print each broken link as JSON line once its found.`)
+
+	flagSet.StringVar(&opts.StateDir, `state`, ``,
+		`Directory to save the state of scan, for resuming the scan.`)
+
+	flagSet.BoolVar(&opts.Source, `source`, false,
+		`Scan links inside the Markdown and AsciiDoc files in DIRECTORY.`)
+
+	return cmd
+}
+
+func (cmd *cmdBrokenlinks) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and print the result as JSON to
+// standard output.
+// On SIGINT, the scan is stopped, the partial result is printed, and the
+// program exit with status 1.
+func (cmd *cmdBrokenlinks) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+
+	err = cmd.crawl.setLogger(&opts)
+	if err != nil {
+		return err
+	}
+
+	// On SIGINT, the scan is stopped and the partial result is
+	// printed.
+	var ctx, stop = signal.NotifyContext(context.Background(),
+		os.Interrupt)
+	if cmd.stream {
+		opts.OnBroken = printBroken
+	}
+
+	var result *brokenlinks.Result
+	result, err = brokenlinks.ScanContext(ctx, opts)
+	stop()
+	var isInterrupted = errors.Is(err, brokenlinks.ErrInterrupted)
+	if err != nil && !isInterrupted {
+		return err
+	}
+
+	var resultJson []byte
+	if cmd.stream {
+		resultJson, err = json.Marshal(result)
+	} else {
+		resultJson, err = json.MarshalIndent(result, ``, `  `)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", resultJson)
+
+	if isInterrupted {
+		if opts.StateDir != `` {
+			log.Printf(`Scan interrupted, run with "-resume"`+
+				` to continue from state in %s.`,
+				opts.StateDir)
+		} else {
+			log.Printf(`Scan interrupted.`)
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+// streamBroken the broken link printed by printBroken.
+type streamBroken struct {
+	Page string `json:"page"`
+	brokenlinks.Broken
+}
+
+// printBroken print the broken link inside the page as single line JSON
+// to standard output.
+func printBroken(page string, broken brokenlinks.Broken) {
+	var line, err = json.Marshal(streamBroken{
+		Page:   page,
+		Broken: broken,
+	})
+	if err != nil {
+		log.Printf(`printBroken: %s`, err)
+		return
+	}
+	fmt.Printf("%s\n", line)
+}
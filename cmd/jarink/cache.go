@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"git.sr.ht/~shulhan/jarink"
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+	"git.sr.ht/~shulhan/jarink/internal"
+)
+
+// cmdCache the "cache" command.
+type cmdCache struct {
+	flagSet *flag.FlagSet
+
+	// file the path to cache file.
+	// If its empty, the default cache file is used.
+	file string
+}
+
+func newCmdCache() (cmd *cmdCache) {
+	cmd = &cmdCache{}
+	cmd.flagSet = newFlagSet(`cache`,
+		`[OPTIONS] <list | show | delete | prune | clear | export | import> [args...]`,
+		`Inspect and manage the cache of external links.`)
+	cmd.flagSet.StringVar(&cmd.file, `cache`, ``,
+		`Path to the cache file, either JSON file or bbolt database`+
+			` with extension ".db".`)
+	return cmd
+}
+
+func (cmd *cmdCache) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run the "cache" command with list of arguments, where the first
+// argument is the sub command.
+func (cmd *cmdCache) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+	args = cmd.flagSet.Args()
+	if len(args) == 0 {
+		return fmt.Errorf(`%w: missing sub command`, errUsage)
+	}
+
+	var subcmd = strings.ToLower(args[0])
+	args = args[1:]
+
+	var cache jarink.CacheStore
+	cache, err = jarink.OpenCache(cmd.file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		var errClose = cache.Close()
+		if err == nil {
+			err = errClose
+		}
+	}()
+
+	switch subcmd {
+	case `list`:
+		return cacheList(cache, os.Stdout)
+
+	case `show`:
+		if len(args) == 0 {
+			return fmt.Errorf(`%w: missing URL`, errUsage)
+		}
+		var scannedLink *jarink.ScannedLink
+		scannedLink, err = cache.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if scannedLink == nil {
+			return fmt.Errorf(`cache show: %q not found`, args[0])
+		}
+		var out []byte
+		out, err = json.MarshalIndent(scannedLink, ``, `  `)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", out)
+		return nil
+
+	case `delete`:
+		if len(args) == 0 {
+			return fmt.Errorf(`%w: missing pattern`, errUsage)
+		}
+		var pattern = args[0]
+		var isMatch = func(scannedLink *jarink.ScannedLink) bool {
+			return internal.MatchWildcard(pattern, scannedLink.Url)
+		}
+		var n int
+		n, err = cacheDelete(cache, isMatch)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d link(s) deleted\n", n)
+
+	case `prune`:
+		var flagPrune = newFlagSet(`cache prune`,
+			`[-older-than=<duration>]`,
+			`Delete the links that has been scanned before the duration.`)
+		var olderThan = flagPrune.Duration(`older-than`,
+			brokenlinks.DefaultCacheTTL,
+			`Delete the links that scanned before the duration.`)
+		err = parseFlags(flagPrune, cfg, args)
+		if err != nil {
+			return err
+		}
+		var deadline = internal.TimeNow().Add(-*olderThan)
+		var isOlder = func(scannedLink *jarink.ScannedLink) bool {
+			return scannedLink.CheckedAt.Before(deadline)
+		}
+		var n int
+		n, err = cacheDelete(cache, isOlder)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d link(s) pruned\n", n)
+
+	case `clear`:
+		var n int
+		n, err = cacheDelete(cache, func(*jarink.ScannedLink) bool {
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d link(s) deleted\n", n)
+
+	case `export`:
+		if len(args) == 0 {
+			return cacheExport(cache, os.Stdout)
+		}
+		var file *os.File
+		file, err = os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		err = cacheExport(cache, file)
+		var errClose = file.Close()
+		if err != nil {
+			return err
+		}
+		return errClose
+
+	case `import`:
+		if len(args) == 0 {
+			return fmt.Errorf(`%w: missing file`, errUsage)
+		}
+		var n int
+		n, err = cacheImport(cache, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d link(s) imported\n", n)
+
+	default:
+		return fmt.Errorf(`%w: unknown sub command %q`, errUsage,
+			subcmd)
+	}
+
+	return cache.Save()
+}
+
+// cacheList print each scanned link in the cache, one per line, with the
+// following format,
+//
+//	<checked_at> <response_code> <size> <url> [<error>]
+func cacheList(cache jarink.CacheStore, out io.Writer) error {
+	return cache.Iterate(func(scannedLink *jarink.ScannedLink) bool {
+		var checkedAt = `-`
+		if !scannedLink.CheckedAt.IsZero() {
+			checkedAt = scannedLink.CheckedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(out, "%s %d %d %s", checkedAt,
+			scannedLink.ResponseCode, scannedLink.Size,
+			scannedLink.Url)
+		if scannedLink.Error != `` {
+			fmt.Fprintf(out, " %q", scannedLink.Error)
+		}
+		fmt.Fprintln(out)
+		return true
+	})
+}
+
+// cacheDelete delete the scanned links in cache that match with the
+// function isMatch and return the number of deleted links.
+func cacheDelete(
+	cache jarink.CacheStore, isMatch func(*jarink.ScannedLink) bool,
+) (n int, err error) {
+	var listUrl []string
+	err = cache.Iterate(func(scannedLink *jarink.ScannedLink) bool {
+		if isMatch(scannedLink) {
+			listUrl = append(listUrl, scannedLink.Url)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, url := range listUrl {
+		err = cache.Delete(url)
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// cacheExport write all of scanned links in the cache as JSON, using the
+// same format as the cache file.
+func cacheExport(cache jarink.CacheStore, out io.Writer) (err error) {
+	var exported = jarink.Cache{
+		ScannedLinks: map[string]*jarink.ScannedLink{},
+	}
+	err = cache.Iterate(func(scannedLink *jarink.ScannedLink) bool {
+		exported.ScannedLinks[scannedLink.Url] = scannedLink
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	var enc = json.NewEncoder(out)
+	enc.SetIndent(``, `  `)
+	return enc.Encode(&exported)
+}
+
+// cacheImport read the scanned links from JSON file, using the same
+// format as the cache file, and store it into cache.
+// The link that already exist in cache is replaced only if the imported
+// one is scanned later.
+func cacheImport(cache jarink.CacheStore, file string) (n int, err error) {
+	var content []byte
+	content, err = os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	var imported jarink.Cache
+	err = json.Unmarshal(content, &imported)
+	if err != nil {
+		return 0, fmt.Errorf(`%s: %w`, file, err)
+	}
+
+	for url, scannedLink := range imported.ScannedLinks {
+		if scannedLink == nil {
+			continue
+		}
+		scannedLink.Url = url
+		var current *jarink.ScannedLink
+		current, err = cache.Get(url)
+		if err != nil {
+			return n, err
+		}
+		if current != nil &&
+			!scannedLink.CheckedAt.After(current.CheckedAt) {
+			continue
+		}
+		err = cache.Set(scannedLink)
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
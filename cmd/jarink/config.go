@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/ini"
+)
+
+// defConfigFile the configuration file in the current directory that
+// loaded when the option "-config" is not set.
+const defConfigFile = `.jarink.ini`
+
+// configSectionGlobal the section in configuration file where its
+// variables applied to all commands that have option with the same name.
+const configSectionGlobal = `jarink`
+
+// config contains the options for commands loaded from INI file.
+//
+// The variables in section "jarink" are applied to any command that has
+// option with the same name, while the variables in the section with
+// the same name as command, for example "brokenlinks", are applied only
+// to that command.
+// The sub command use the sub section, for example `[cache "prune"]`.
+// The options from command line override the options in configuration
+// file.
+type config struct {
+	in *ini.Ini
+
+	file string
+}
+
+// loadConfig load the configuration from file.
+// If the file is empty, it load the file [defConfigFile] in the current
+// directory, if its exist.
+func loadConfig(file string) (cfg *config, err error) {
+	cfg = &config{
+		file: file,
+	}
+	if file == `` {
+		cfg.file = defConfigFile
+		_, err = os.Stat(cfg.file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+	}
+	cfg.in, err = ini.Open(cfg.file)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// apply set the options in flagSet using the variables in section
+// [configSectionGlobal] and then in the section for the command.
+// The flagSet name is the command name, optionally followed by space and
+// the sub command name.
+func (cfg *config) apply(flagSet *flag.FlagSet) (err error) {
+	if cfg == nil || cfg.in == nil {
+		return nil
+	}
+
+	for key, val := range cfg.vars(configSectionGlobal, ``) {
+		if flagSet.Lookup(key) == nil {
+			continue
+		}
+		err = cfg.set(flagSet, key, val)
+		if err != nil {
+			return err
+		}
+	}
+
+	var secName, subName, _ = strings.Cut(flagSet.Name(), ` `)
+	for key, val := range cfg.vars(secName, subName) {
+		if flagSet.Lookup(key) == nil {
+			return fmt.Errorf(`%s: unknown option %q for %q`,
+				cfg.file, key, flagSet.Name())
+		}
+		err = cfg.set(flagSet, key, val)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vars return the variables in section and sub section, by its key.
+func (cfg *config) vars(secName, subName string) (vars map[string]string) {
+	vars = map[string]string{}
+	for key, vals := range cfg.in.AsMap(secName, subName) {
+		// Without sub section, the key is prefixed with section
+		// name and empty sub section.
+		var idx = strings.LastIndexByte(key, ':')
+		key = strings.ToLower(key[idx+1:])
+		vars[key] = vals[len(vals)-1]
+	}
+	return vars
+}
+
+// set the option key in flagSet with value val.
+// The boolean option without value is set to true.
+func (cfg *config) set(flagSet *flag.FlagSet, key, val string) error {
+	var fl = flagSet.Lookup(key)
+	var boolFlag, ok = fl.Value.(interface{ IsBoolFlag() bool })
+	if ok && boolFlag.IsBoolFlag() && val == `` {
+		val = `true`
+	}
+	var err = flagSet.Set(key, val)
+	if err != nil {
+		return fmt.Errorf(`%s: invalid value %q for %q: %w`, cfg.file,
+			val, key, err)
+	}
+	return nil
+}
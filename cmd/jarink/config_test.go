@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~shulhan/pakakeh.go/lib/test"
+)
+
+func TestParseFlags(t *testing.T) {
+	var cfgFile = filepath.Join(t.TempDir(), `jarink.ini`)
+	var content = `
+[jarink]
+verbose
+cache = global.db
+not-an-option = ignored
+
+[brokenlinks]
+cache = brokenlinks.db
+max-concurrent = 10
+
+[cache]
+cache = cache.db
+
+[cache "prune"]
+older-than = 24h
+
+[graph]
+unknown = 1
+
+[seo]
+max-concurrent = abc
+`
+	var err = os.WriteFile(cfgFile, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg *config
+	cfg, err = loadConfig(cfgFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		exp    map[string]string
+		name   string
+		expErr string
+		args   []string
+	}
+	var listCase = []testCase{{
+		// The bare boolean key in section "jarink" set to true and
+		// the key in command section override section "jarink".
+		name: `brokenlinks`,
+		exp: map[string]string{
+			`cache`:          `brokenlinks.db`,
+			`max-concurrent`: `10`,
+			`older-than`:     ``,
+			`verbose`:        `true`,
+		},
+	}, {
+		// The options from command line override the configuration.
+		name: `brokenlinks`,
+		args: []string{`-cache=args.db`, `-verbose=false`},
+		exp: map[string]string{
+			`cache`:          `args.db`,
+			`max-concurrent`: `10`,
+			`older-than`:     ``,
+			`verbose`:        `false`,
+		},
+	}, {
+		// The sub command use the sub section only.
+		name: `cache prune`,
+		exp: map[string]string{
+			`cache`:          `global.db`,
+			`max-concurrent`: `0`,
+			`older-than`:     `24h`,
+			`verbose`:        `true`,
+		},
+	}, {
+		name:   `graph`,
+		expErr: cfgFile + `: unknown option "unknown" for "graph"`,
+	}, {
+		name: `seo`,
+		expErr: cfgFile + `: invalid value "abc" for "max-concurrent":` +
+			` parse error`,
+	}, {
+		name:   `brokenlinks`,
+		args:   []string{`-not-an-option`},
+		expErr: `invalid flag: flag provided but not defined: -not-an-option`,
+	}}
+
+	for _, tc := range listCase {
+		var flagSet = flag.NewFlagSet(tc.name, flag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.String(`cache`, ``, ``)
+		flagSet.Int(`max-concurrent`, 0, ``)
+		flagSet.String(`older-than`, ``, ``)
+		flagSet.Bool(`verbose`, false, ``)
+
+		err = parseFlags(flagSet, cfg, tc.args)
+		if err != nil {
+			test.Assert(t, tc.name+`: error`, tc.expErr, err.Error())
+			continue
+		}
+		test.Assert(t, tc.name+`: error`, tc.expErr, ``)
+
+		var got = map[string]string{}
+		flagSet.VisitAll(func(fl *flag.Flag) {
+			got[fl.Name] = fl.Value.String()
+		})
+		test.Assert(t, tc.name, tc.exp, got)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdCrawl the "crawl" command.
+type cmdCrawl struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	format string
+	output string
+}
+
+func newCmdCrawl() (cmd *cmdCrawl) {
+	cmd = &cmdCrawl{}
+	cmd.flagSet = newFlagSet(`crawl`, `[OPTIONS] <URL | DIRECTORY>`,
+		`Scan the website or local directory and print all links found`+
+			` with their status, content type, size, response time,`+
+			` depth, and number of inbound links.`)
+
+	var flagSet = cmd.flagSet
+
+	cmd.crawl.register(flagSet, &cmd.opts)
+
+	flagSet.StringVar(&cmd.format, `format`, `json`,
+		`Format of inventory, either "json" or "csv".`)
+
+	flagSet.StringVar(&cmd.output, `output`, ``,
+		`Write the inventory to file instead of standard output.`)
+
+	return cmd
+}
+
+func (cmd *cmdCrawl) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and write the inventory of links
+// in the format set by option "-format".
+// On SIGINT, the scan is stopped and the partial inventory is written.
+func (cmd *cmdCrawl) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+	opts.Inventory = true
+
+	var write func(brokenlinks.Inventory, io.Writer) error
+	switch cmd.format {
+	case `json`:
+		write = brokenlinks.Inventory.WriteJSON
+	case `csv`:
+		write = brokenlinks.Inventory.WriteCSV
+	default:
+		return fmt.Errorf(`%w: invalid format %q`, errUsage,
+			cmd.format)
+	}
+
+	var (
+		result        *brokenlinks.Result
+		isInterrupted bool
+	)
+	result, isInterrupted, err = cmd.crawl.scan(opts)
+	if err != nil {
+		return err
+	}
+
+	err = writeOutput(cmd.output, func(out io.Writer) error {
+		return write(result.Inventory, out)
+	})
+	if err != nil {
+		return err
+	}
+
+	if isInterrupted {
+		log.Printf(`Scan interrupted.`)
+		os.Exit(1)
+	}
+	return nil
+}
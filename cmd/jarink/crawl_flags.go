@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// crawlFlags the options for commands that crawl the website.
+type crawlFlags struct {
+	logFormat string
+	logLevel  string
+}
+
+// register the crawl options into flagSet, where the value of each option
+// stored in opts.
+func (crawl *crawlFlags) register(flagSet *flag.FlagSet,
+	opts *brokenlinks.Options,
+) {
+	flagSet.StringVar(&opts.CacheFile, `cache`, ``,
+		`Path to the cache file, either JSON file or bbolt database`+
+			` with extension ".db".`)
+
+	flagSet.DurationVar(&opts.CacheTTL, `cache-ttl`,
+		brokenlinks.DefaultCacheTTL,
+		`Duration where the successful external link in cache is not`+
+			` scanned again.`)
+
+	flagSet.DurationVar(&opts.CacheFailTTL, `cache-fail-ttl`,
+		brokenlinks.DefaultCacheFailTTL,
+		`Duration where the failed external link in cache is not`+
+			` scanned again.`)
+
+	flagSet.StringVar(&opts.DiskDir, `disk-dir`, ``,
+		`Directory to store the seen links and the links waiting to be`+
+			` scanned, instead of in memory.`)
+
+	flagSet.StringVar(&opts.IgnoreStatus, `ignore-status`, ``,
+		`Comma separated HTTP response status code to be ignored.`)
+
+	flagSet.BoolVar(&opts.Insecure, `insecure`, false,
+		`Do not report as error on server with invalid certificates.`)
+
+	crawl.registerLog(flagSet, opts)
+
+	flagSet.IntVar(&opts.MaxConcurrent, `max-concurrent`,
+		brokenlinks.DefaultMaxConcurrent,
+		`Maximum number of links scanned at the same time.`)
+
+	flagSet.BoolVar(&opts.NoCache, `no-cache`, false,
+		`Do not read and write the external links from and to cache.`)
+
+	flagSet.BoolVar(&opts.RefreshCache, `refresh-cache`, false,
+		`Scan all external links again and write the result to cache.`)
+
+	flagSet.BoolVar(&opts.SkipCode, `skip-code`, false,
+		`Do not scan links inside the "code" and "pre" elements.`)
+}
+
+// registerLog register the options for printing log into flagSet.
+func (crawl *crawlFlags) registerLog(flagSet *flag.FlagSet,
+	opts *brokenlinks.Options,
+) {
+	flagSet.BoolVar(&opts.IsVerbose, `verbose`, false,
+		`Print additional information while running.`+
+			` This option is equal to "-log-level=debug".`)
+
+	flagSet.StringVar(&crawl.logFormat, `log-format`, `text`,
+		`Format of log, either "text" or "json".`)
+
+	flagSet.StringVar(&crawl.logLevel, `log-level`, `warn`,
+		`Minimum level of log to be printed: debug, info, warn, or error.`)
+}
+
+// setLogger set the opts.Logger based on the option "log-format" and
+// "log-level".
+func (crawl *crawlFlags) setLogger(opts *brokenlinks.Options) (err error) {
+	var logLevel = crawl.logLevel
+	if opts.IsVerbose {
+		logLevel = `debug`
+	}
+	opts.Logger, err = newLogger(crawl.logFormat, logLevel)
+	return err
+}
+
+// scan the website or local directory using opts.
+// On SIGINT, the scan is stopped and the partial result is returned with
+// isInterrupted set to true.
+func (crawl *crawlFlags) scan(opts brokenlinks.Options) (
+	result *brokenlinks.Result, isInterrupted bool, err error,
+) {
+	err = crawl.setLogger(&opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ctx, stop = signal.NotifyContext(context.Background(),
+		os.Interrupt)
+
+	result, err = brokenlinks.ScanContext(ctx, opts)
+	stop()
+	isInterrupted = errors.Is(err, brokenlinks.ErrInterrupted)
+	if err != nil && !isInterrupted {
+		return nil, false, err
+	}
+	return result, isInterrupted, nil
+}
+
+// writeOutput call the function write with the file, or with standard
+// output if the file is empty.
+func writeOutput(file string, write func(io.Writer) error) (err error) {
+	if file == `` {
+		return write(os.Stdout)
+	}
+	var out *os.File
+	out, err = os.Create(file)
+	if err != nil {
+		return err
+	}
+	err = write(out)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// printIssues print the issues on each page as JSON to standard output.
+func printIssues(issues map[string][]brokenlinks.Issue) (err error) {
+	var issuesJson []byte
+	issuesJson, err = json.MarshalIndent(issues, ``, `  `)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", issuesJson)
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdDiff the "diff" command.
+type cmdDiff struct {
+	flagSet *flag.FlagSet
+}
+
+func newCmdDiff() (cmd *cmdDiff) {
+	cmd = &cmdDiff{}
+	cmd.flagSet = newFlagSet(`diff`, `<OLD JSON> <NEW JSON>`,
+		`Compare two results of brokenlinks command.`)
+	return cmd
+}
+
+func (cmd *cmdDiff) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run compare the old and new result in the first and second arguments,
+// and print the differences as JSON to standard output.
+func (cmd *cmdDiff) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var oldFile = cmd.flagSet.Arg(0)
+	var newFile = cmd.flagSet.Arg(1)
+	if oldFile == `` || newFile == `` {
+		return fmt.Errorf(`%w: missing argument old or new result file`,
+			errUsage)
+	}
+
+	var (
+		oldResult *brokenlinks.Result
+		newResult *brokenlinks.Result
+	)
+	oldResult, err = brokenlinks.LoadResult(oldFile)
+	if err != nil {
+		return err
+	}
+	newResult, err = brokenlinks.LoadResult(newFile)
+	if err != nil {
+		return err
+	}
+
+	var diff = brokenlinks.Diff(oldResult, newResult)
+
+	var diffJson []byte
+	diffJson, err = json.MarshalIndent(diff, ``, `  `)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", diffJson)
+	return nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdFix the "fix" command.
+type cmdFix struct {
+	flagSet *flag.FlagSet
+
+	// url the base URL of website where the directory served.
+	url string
+
+	dryRun bool
+}
+
+func newCmdFix() (cmd *cmdFix) {
+	cmd = &cmdFix{}
+	cmd.flagSet = newFlagSet(`fix`, `[OPTIONS] <JSON> <DIRECTORY>`,
+		`Replace the broken links in HTML and Markdown files inside`+
+			` DIRECTORY.`)
+	cmd.flagSet.BoolVar(&cmd.dryRun, `dry-run`, false,
+		`Print the changes in unified diff without modifying the files.`)
+	cmd.flagSet.StringVar(&cmd.url, `url`, ``,
+		`The base URL of website where the directory served.`)
+	return cmd
+}
+
+func (cmd *cmdFix) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run the "fix" command with list of arguments, where the arguments are
+// the JSON file that contains the result of brokenlinks or redirect map,
+// and the directory to be fixed.
+func (cmd *cmdFix) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var jsonFile = cmd.flagSet.Arg(0)
+	var dir = cmd.flagSet.Arg(1)
+	if jsonFile == `` || dir == `` {
+		return fmt.Errorf(`%w: missing JSON file or directory`,
+			errUsage)
+	}
+
+	var opts = brokenlinks.FixOptions{
+		Dir:    dir,
+		Url:    cmd.url,
+		DryRun: cmd.dryRun,
+	}
+	opts.Replacements, err = brokenlinks.LoadReplacements(jsonFile)
+	if err != nil {
+		return err
+	}
+
+	var result *brokenlinks.FixResult
+	result, err = brokenlinks.Fix(opts)
+	if err != nil {
+		return err
+	}
+	if cmd.dryRun {
+		fmt.Print(result.Diff)
+		return nil
+	}
+	for _, file := range result.Files {
+		fmt.Printf("%s\n", file)
+	}
+	fmt.Printf("%d link(s) replaced in %d file(s)\n", result.Count,
+		len(result.Files))
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdGraph the "graph" command.
+type cmdGraph struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	format string
+	output string
+}
+
+func newCmdGraph() (cmd *cmdGraph) {
+	cmd = &cmdGraph{}
+	cmd.flagSet = newFlagSet(`graph`, `[OPTIONS] <URL | DIRECTORY>`,
+		`Scan the website or local directory and print the graph of`+
+			` pages and links.`)
+
+	var flagSet = cmd.flagSet
+
+	cmd.crawl.register(flagSet, &cmd.opts)
+
+	flagSet.StringVar(&cmd.format, `format`, `json`,
+		`Format of graph, either "json", "dot", or "graphml".`)
+
+	flagSet.StringVar(&cmd.output, `output`, ``,
+		`Write the graph to file instead of standard output.`)
+
+	return cmd
+}
+
+func (cmd *cmdGraph) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and write the link graph in the
+// format set by option "-format".
+// On SIGINT, the scan is stopped and the partial graph is written.
+func (cmd *cmdGraph) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+	opts.Graph = true
+
+	var write func(*brokenlinks.Graph, io.Writer) error
+	switch cmd.format {
+	case `json`:
+		write = (*brokenlinks.Graph).WriteJSON
+	case `dot`:
+		write = (*brokenlinks.Graph).WriteDOT
+	case `graphml`:
+		write = (*brokenlinks.Graph).WriteGraphML
+	default:
+		return fmt.Errorf(`%w: invalid format %q`, errUsage,
+			cmd.format)
+	}
+
+	var (
+		result        *brokenlinks.Result
+		isInterrupted bool
+	)
+	result, isInterrupted, err = cmd.crawl.scan(opts)
+	if err != nil {
+		return err
+	}
+
+	err = writeOutput(cmd.output, func(out io.Writer) error {
+		return write(result.Graph, out)
+	})
+	if err != nil {
+		return err
+	}
+
+	if isInterrupted {
+		log.Printf(`Scan interrupted.`)
+		os.Exit(1)
+	}
+	return nil
+}
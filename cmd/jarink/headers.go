@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdHeaders the "headers" command.
+type cmdHeaders struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	single bool
+}
+
+// headersReport the output of "headers" command.
+type headersReport struct {
+	Issues  map[string][]brokenlinks.Issue `json:"issues"`
+	Summary *brokenlinks.HeadersSummary    `json:"summary"`
+}
+
+func newCmdHeaders() (cmd *cmdHeaders) {
+	cmd = &cmdHeaders{}
+	cmd.flagSet = newFlagSet(`headers`, `[OPTIONS] <URL>`,
+		`Scan the website and print the issues on the security`+
+			` headers of each HTML page, with the site-wide summary.`)
+
+	var flagSet = cmd.flagSet
+
+	cmd.crawl.register(flagSet, &cmd.opts)
+
+	flagSet.BoolVar(&cmd.single, `single`, false,
+		`Audit only the page in URL, without scanning the links in it.`)
+
+	return cmd
+}
+
+func (cmd *cmdHeaders) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and print the issues on the
+// security headers of each page and the summary as JSON to standard
+// output.
+// On SIGINT, the scan is stopped and the issues on pages that has been
+// scanned are printed.
+func (cmd *cmdHeaders) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+	opts.Headers = true
+
+	var (
+		result        *brokenlinks.Result
+		isInterrupted bool
+	)
+	if cmd.single {
+		err = cmd.crawl.setLogger(&opts)
+		if err != nil {
+			return err
+		}
+		var ctx, stop = signal.NotifyContext(context.Background(),
+			os.Interrupt)
+		result, err = brokenlinks.AuditHeaders(ctx, opts)
+		stop()
+		if errors.Is(err, brokenlinks.ErrInterrupted) {
+			log.Printf(`Audit interrupted.`)
+			os.Exit(1)
+		}
+	} else {
+		result, isInterrupted, err = cmd.crawl.scan(opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	var report = headersReport{
+		Issues:  result.Issues,
+		Summary: result.HeadersSummary,
+	}
+	var reportJson []byte
+	reportJson, err = json.MarshalIndent(report, ``, `  `)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", reportJson)
+
+	if isInterrupted {
+		log.Printf(`Scan interrupted.`)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -4,77 +4,98 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
 
 	"git.sr.ht/~shulhan/jarink"
-	"git.sr.ht/~shulhan/jarink/brokenlinks"
 )
 
-func main() {
-	log.SetFlags(0)
+// errUsage returned by the command when the option or argument is
+// missing or invalid.
+var errUsage = errors.New(`invalid usage`)
 
-	var (
-		optIgnoreStatus string
-		optInsecure     bool
-		optIsVerbose    bool
-		optPastResult   string
-	)
+// command define the interface for each command.
+type command interface {
+	// usage print the usage of command to standard error.
+	usage()
 
-	flag.StringVar(&optIgnoreStatus, `ignore-status`, ``,
-		`Comma separated HTTP response status code to be ignored.`)
+	// run the command with the configuration and list of arguments
+	// after the command name.
+	run(cfg *config, args []string) error
+}
 
-	flag.BoolVar(&optInsecure, `insecure`, false,
-		`Do not report as error on server with invalid certificates.`)
+// newCommand return the command by its name, or nil if the command is
+// unknown.
+func newCommand(name string) command {
+	switch name {
+	case `a11y`:
+		return newCmdA11y()
+	case `brokenlinks`:
+		return newCmdBrokenlinks()
+	case `cache`:
+		return newCmdCache()
+	case `crawl`:
+		return newCmdCrawl()
+	case `diff`:
+		return newCmdDiff()
+	case `fix`:
+		return newCmdFix()
+	case `graph`:
+		return newCmdGraph()
+	case `headers`:
+		return newCmdHeaders()
+	case `seo`:
+		return newCmdSeo()
+	case `sitemap`:
+		return newCmdSitemap()
+	case `sitemap-check`:
+		return newCmdSitemapCheck()
+	}
+	return nil
+}
 
-	flag.BoolVar(&optIsVerbose, `verbose`, false,
-		`Print additional information while running.`)
+func main() {
+	log.SetFlags(0)
 
-	flag.StringVar(&optPastResult, `past-result`, ``,
-		`Scan only pages with broken links from the past JSON result.`)
+	var optConfig string
 
-	flag.Parse()
+	flag.StringVar(&optConfig, `config`, ``,
+		`Path to the configuration file, default to "`+defConfigFile+
+			`" in current directory if its exist.`)
 
-	var cmd = flag.Arg(0)
-	cmd = strings.ToLower(cmd)
-	switch cmd {
-	case `brokenlinks`:
-		var opts = brokenlinks.Options{
-			IgnoreStatus:   optIgnoreStatus,
-			Insecure:       optInsecure,
-			IsVerbose:      optIsVerbose,
-			PastResultFile: optPastResult,
-		}
+	flag.Usage = func() {
+		var out = flag.CommandLine.Output()
+		fmt.Fprintf(out, "Usage: jarink [-config=<FILE>] <COMMAND>"+
+			" [OPTIONS] <args...>\n\n")
+		fmt.Fprintf(out, "Run \"jarink help\" for the list of"+
+			" commands.\n\nOptions:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-		opts.Url = flag.Arg(1)
-		if opts.Url == "" {
-			log.Printf(`Missing argument URL to be scanned.`)
-			goto invalid_command
-		}
+	var name = strings.ToLower(flag.Arg(0))
+	var args = flag.Args()
+	if len(args) > 0 {
+		args = args[1:]
+	}
 
-		var (
-			result *brokenlinks.Result
-			err    error
-		)
-		result, err = brokenlinks.Scan(opts)
-		if err != nil {
-			log.Fatal(err.Error())
+	switch name {
+	case `help`:
+		if len(args) == 0 {
+			log.Println(jarink.GoEmbedReadme)
+			return
 		}
-
-		var resultJson []byte
-		resultJson, err = json.MarshalIndent(result, ``, `  `)
-		if err != nil {
-			log.Fatal(err.Error())
+		var cmd = newCommand(strings.ToLower(args[0]))
+		if cmd == nil {
+			log.Printf(`Unknown command %q`, args[0])
+			goto invalid_command
 		}
-		fmt.Printf("%s\n", resultJson)
-		return
-
-	case `help`:
-		log.Println(jarink.GoEmbedReadme)
+		cmd.usage()
 		return
 
 	case `version`:
@@ -82,10 +103,107 @@ func main() {
 		return
 
 	default:
-		log.Printf(`Missing or invalid command %q`, cmd)
+		var cmd = newCommand(name)
+		if cmd == nil {
+			log.Printf(`Missing or invalid command %q`, name)
+			goto invalid_command
+		}
+		runCommand(name, cmd, optConfig, args)
+		return
 	}
 
 invalid_command:
 	log.Printf(`Run "jarink help" for usage.`)
 	os.Exit(1)
 }
+
+// runCommand load the configuration file and run the command.
+// On error, it print the error and exit the program with status 1.
+func runCommand(name string, cmd command, configFile string, args []string) {
+	var cfg, err = loadConfig(configFile)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	err = cmd.run(cfg, args)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		// The usage has been printed by the flag set.
+	case errors.Is(err, errInvalidFlag):
+		// The error and usage has been printed by the flag set.
+		os.Exit(1)
+	case errors.Is(err, errUsage):
+		log.Printf(`%s: %s`, name, err)
+		cmd.usage()
+		os.Exit(1)
+	default:
+		log.Printf(`%s: %s`, name, err)
+		os.Exit(1)
+	}
+}
+
+// errInvalidFlag returned by parseFlags when the flag set failed to parse
+// the arguments.
+var errInvalidFlag = errors.New(`invalid flag`)
+
+// newFlagSet create new flag set for the command name, with synopsis of
+// its arguments and short description for the usage.
+func newFlagSet(name, synopsis, desc string) (flagSet *flag.FlagSet) {
+	flagSet = flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.Usage = func() {
+		var out = flagSet.Output()
+		fmt.Fprintf(out, "Usage: jarink %s %s\n\n%s\n", name, synopsis,
+			desc)
+		var hasOption bool
+		flagSet.VisitAll(func(*flag.Flag) {
+			hasOption = true
+		})
+		if hasOption {
+			fmt.Fprintf(out, "\nOptions:\n")
+			flagSet.PrintDefaults()
+		}
+	}
+	return flagSet
+}
+
+// parseFlags set the options in flagSet from the configuration and then
+// from the arguments.
+func parseFlags(flagSet *flag.FlagSet, cfg *config, args []string) (
+	err error,
+) {
+	err = cfg.apply(flagSet)
+	if err != nil {
+		return err
+	}
+	err = flagSet.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return fmt.Errorf(`%w: %w`, errInvalidFlag, err)
+	}
+	return err
+}
+
+// newLogger create new [slog.Logger] that write to stderr using the
+// format "text" or "json" and minimum level.
+func newLogger(format, level string) (logger *slog.Logger, err error) {
+	var logp = `newLogger`
+	var handlerOpts = &slog.HandlerOptions{}
+
+	var logLevel slog.Level
+	err = logLevel.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, fmt.Errorf(`%s: invalid log level %q`, logp, level)
+	}
+	handlerOpts.Level = logLevel
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case ``, `text`:
+		handler = slog.NewTextHandler(os.Stderr, handlerOpts)
+	case `json`:
+		handler = slog.NewJSONHandler(os.Stderr, handlerOpts)
+	default:
+		return nil, fmt.Errorf(`%s: invalid log format %q`, logp, format)
+	}
+	return slog.New(handler), nil
+}
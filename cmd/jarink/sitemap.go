@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdSitemap the "sitemap" command.
+type cmdSitemap struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	url    string
+	output string
+}
+
+func newCmdSitemap() (cmd *cmdSitemap) {
+	cmd = &cmdSitemap{}
+	cmd.flagSet = newFlagSet(`sitemap`, `[OPTIONS] <URL | DIRECTORY>`,
+		`Scan the website or local directory and write the pages that`+
+			` can be indexed by search engine into "sitemap.xml".`)
+
+	var flagSet = cmd.flagSet
+
+	cmd.crawl.register(flagSet, &cmd.opts)
+
+	flagSet.StringVar(&cmd.output, `output`, `.`,
+		`Directory where the sitemap files written.`)
+
+	flagSet.StringVar(&cmd.url, `url`, ``,
+		`The base URL of website where the DIRECTORY is served, for`+
+			` example "https://web.tld".`)
+
+	return cmd
+}
+
+func (cmd *cmdSitemap) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run scan the URL in the first argument and write the sitemap into
+// directory set by option "-output".
+func (cmd *cmdSitemap) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be scanned`,
+			errUsage)
+	}
+	opts.Sitemap = true
+
+	var baseUrl = cmd.url
+	if baseUrl == `` {
+		// Use the root of website as the location of sitemap
+		// index.
+		var scanUrl *url.URL
+		scanUrl, err = url.Parse(opts.Url)
+		if err == nil && (scanUrl.Scheme == `http` ||
+			scanUrl.Scheme == `https`) {
+			baseUrl = scanUrl.Scheme + `://` + scanUrl.Host + `/`
+		}
+	}
+	if baseUrl == `` {
+		return fmt.Errorf(`%w: missing option -url for DIRECTORY`,
+			errUsage)
+	}
+
+	var (
+		result        *brokenlinks.Result
+		isInterrupted bool
+	)
+	result, isInterrupted, err = cmd.crawl.scan(opts)
+	if err != nil {
+		return err
+	}
+	if isInterrupted {
+		// The partial sitemap is not written, since it may
+		// replace the complete one.
+		log.Printf(`Scan interrupted.`)
+		os.Exit(1)
+	}
+
+	var files []string
+	files, err = result.Sitemap.Write(cmd.output, baseUrl)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Printf("%s\n", file)
+	}
+	fmt.Printf("%d page(s) written\n", len(result.Sitemap))
+	return nil
+}
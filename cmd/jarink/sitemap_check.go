@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 M. Shulhan <ms@kilabit.info>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"git.sr.ht/~shulhan/jarink/brokenlinks"
+)
+
+// cmdSitemapCheck the "sitemap-check" command.
+type cmdSitemapCheck struct {
+	flagSet *flag.FlagSet
+
+	crawl crawlFlags
+
+	opts brokenlinks.Options
+
+	stream bool
+}
+
+func newCmdSitemapCheck() (cmd *cmdSitemapCheck) {
+	cmd = &cmdSitemapCheck{}
+	cmd.flagSet = newFlagSet(`sitemap-check`, `[OPTIONS] <URL>`,
+		`Check the robots.txt, the sitemaps, and the URLs in the`+
+			` sitemaps of the website.`)
+
+	var (
+		flagSet = cmd.flagSet
+		opts    = &cmd.opts
+	)
+
+	cmd.crawl.registerLog(flagSet, opts)
+
+	flagSet.BoolVar(&opts.Insecure, `insecure`, false,
+		`Do not report as error on server with invalid certificates.`)
+
+	flagSet.IntVar(&opts.MaxConcurrent, `max-concurrent`,
+		brokenlinks.DefaultMaxConcurrent,
+		`Maximum number of URLs checked at the same time.`)
+
+	flagSet.BoolVar(&cmd.stream, `stream`, false,
+		`Print each finding as JSON line once its found.`)
+
+	return cmd
+}
+
+func (cmd *cmdSitemapCheck) usage() {
+	cmd.flagSet.Usage()
+}
+
+// run check the sitemaps of website in the first argument and print the
+// findings as JSON to standard output, in the same format as the result
+// of brokenlinks.
+func (cmd *cmdSitemapCheck) run(cfg *config, args []string) (err error) {
+	err = parseFlags(cmd.flagSet, cfg, args)
+	if err != nil {
+		return err
+	}
+
+	var opts = cmd.opts
+	opts.Url = cmd.flagSet.Arg(0)
+	if opts.Url == `` {
+		return fmt.Errorf(`%w: missing argument URL to be checked`,
+			errUsage)
+	}
+	err = cmd.crawl.setLogger(&opts)
+	if err != nil {
+		return err
+	}
+	if cmd.stream {
+		opts.OnBroken = printBroken
+	}
+
+	var ctx, stop = signal.NotifyContext(context.Background(),
+		os.Interrupt)
+
+	var result *brokenlinks.Result
+	result, err = brokenlinks.CheckSitemap(ctx, opts)
+	stop()
+	var isInterrupted = errors.Is(err, brokenlinks.ErrInterrupted)
+	if err != nil && !isInterrupted {
+		return err
+	}
+
+	var resultJson []byte
+	if cmd.stream {
+		resultJson, err = json.Marshal(result)
+	} else {
+		resultJson, err = json.MarshalIndent(result, ``, `  `)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", resultJson)
+
+	if isInterrupted {
+		log.Printf(`Check interrupted.`)
+		os.Exit(1)
+	}
+	return nil
+}
@@ -7,10 +7,12 @@ go 1.24.0
 
 require (
 	git.sr.ht/~shulhan/pakakeh.go v0.60.2
+	go.etcd.io/bbolt v1.4.3
 	golang.org/x/net v0.48.0
 )
 
 require (
+	golang.org/x/exp v0.0.0-20251219203646-944ab1f22d93 // indirect
 	golang.org/x/mod v0.31.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // CacheFile return the path to cache file under [os.UserCacheDir] +
@@ -32,3 +33,75 @@ func DefaultCacheFile() (cacheFile string, err error) {
 	cacheFile = filepath.Join(cacheDir, `cache.json`)
 	return cacheFile, nil
 }
+
+// TimeNow return the current time.
+// This variable defined here so the test file can override it.
+var TimeNow = time.Now
+
+// MatchWildcard return true if the value match with pattern, where the
+// character "*" in pattern match zero or more characters.
+func MatchWildcard(pattern, value string) bool {
+	var (
+		px, vx     int
+		star       = -1
+		starValIdx int
+	)
+	for vx < len(value) {
+		switch {
+		case px < len(pattern) && pattern[px] == '*':
+			star = px
+			starValIdx = vx
+			px++
+		case px < len(pattern) && pattern[px] == value[vx]:
+			px++
+			vx++
+		case star >= 0:
+			px = star + 1
+			starValIdx++
+			vx = starValIdx
+		default:
+			return false
+		}
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
+
+// WriteFileAtomic write the content into temporary file in the same
+// directory as file and then rename it into file, so the file is never
+// partially written.
+func WriteFileAtomic(file string, content []byte, perm os.FileMode) (
+	err error,
+) {
+	var tmp *os.File
+	tmp, err = os.CreateTemp(filepath.Dir(file), filepath.Base(file)+`.*`)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		return err
+	}
+	err = tmp.Chmod(perm)
+	if err != nil {
+		return err
+	}
+	err = tmp.Sync()
+	if err != nil {
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}